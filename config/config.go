@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"flag"
+	"hash/fnv"
 	"io"
 	"log"
 	"os"
@@ -10,6 +11,39 @@ import (
 
 var defaultRemoteServiceTimeout = 30000 // in ms
 
+var (
+	defaultCircuitBreakerFailureThreshold = 5
+	defaultCircuitBreakerCoolDown         = 30000 // in ms
+)
+
+var (
+	defaultRetryMaxAttempts = 5
+	defaultRetryBaseDelay   = 30000   // in ms
+	defaultRetryMaxDelay    = 3600000 // in ms
+	defaultRetryMultiplier  = 2.0
+	defaultRetryJitter      = 0.2
+)
+
+var (
+	defaultSchedulerBatchSize          = 2
+	defaultSchedulerInterval           = 120000 // in ms
+	defaultSchedulerJobTimeout         = 30000  // in ms
+	defaultSchedulerConcurrency        = 4
+	defaultSchedulerPriorityAging      = 300000 // in ms
+	defaultLeaseDuration               = 300000 // in ms
+	defaultLeaseReapInterval           = 60000  // in ms
+	defaultLeaderElectionRetryInterval = 10000  // in ms
+)
+
+var defaultTemplatesLocale = "en"
+
+var (
+	defaultOutboxBatchSize   = 100
+	defaultOutboxInterval    = 5000  // in ms
+	defaultOutboxRetryDelay  = 30000 // in ms
+	defaultOutboxHTTPTimeout = 5000  // in ms
+)
+
 type PostgresConfig struct {
 	WriteHost string `json:"writeHost"`
 	WritePort string `json:"writePort"`
@@ -20,13 +54,176 @@ type PostgresConfig struct {
 	DbName    string `json:"dbname"`
 }
 
+type RateLimitConfig struct {
+	Rate     float64 `json:"rate"`     // requests per second, 0 disables rate limiting
+	Burst    int     `json:"burst"`    // requests allowed at once on top of the rate
+	DailyCap int     `json:"dailyCap"` // requests per UTC day, 0 means unlimited
+}
+
+type CircuitBreakerConfig struct {
+	Enabled          bool `json:"enabled"`
+	FailureThreshold int  `json:"failureThreshold"` // consecutive failures that open the breaker
+	CoolDown         int  `json:"coolDown"`         // in ms
+}
+
+// CallbackConfig authenticates the delivery receipts a provider posts back.
+type CallbackConfig struct {
+	// Secrets verify the signature header of every callback, any of them may have signed it. Without
+	// secrets the provider's callbacks are rejected.
+	Secrets []string `json:"secrets"`
+	// Tolerance is how far, in ms, the signing time may be from now, 0 uses the signature package default.
+	Tolerance int `json:"tolerance"`
+}
+
+type SigningConfig struct {
+	// Secrets sign every request, newest first; keep the previous secret listed while receivers rotate.
+	// Empty disables signing.
+	Secrets []string `json:"secrets"`
+}
+
+const (
+	AuthNone   = ""
+	AuthBearer = "bearer"
+	AuthBasic  = "basic"
+	AuthAPIKey = "apiKey"
+	AuthOAuth2 = "oauth2"
+)
+
+type OAuth2Config struct {
+	TokenURL     string   `json:"tokenUrl"`
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	Scopes       []string `json:"scopes"`
+}
+
+type AuthConfig struct {
+	Type     string       `json:"type"` // one of bearer, basic, apiKey, oauth2; empty sends no credentials
+	Token    string       `json:"token"`
+	Username string       `json:"username"`
+	Password string       `json:"password"`
+	Header   string       `json:"header"` // API key header, X-API-Key by default
+	APIKey   string       `json:"apiKey"`
+	OAuth2   OAuth2Config `json:"oauth2"`
+}
+
+// TLSConfig enables mutual TLS with CertFile and KeyFile. CAFile replaces the system roots for
+// webhooks signed by a private authority.
+type TLSConfig struct {
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	CAFile   string `json:"caFile"`
+}
+
+// RequestConfig adapts the request to a provider's API. Body and header values are Go templates
+// over the message (.ID, .Phone, .Content, .Priority, .Tags, .IdempotencyKey); empty fields keep the
+// default POST of {"to","content"} answered with {"messageId"}.
+type RequestConfig struct {
+	Method        string            `json:"method"`
+	Headers       map[string]string `json:"headers"`
+	Body          string            `json:"body"`
+	MessageIDPath string            `json:"messageIdPath"` // JSONPath, e.g. "$.data.id"
+}
+
 type WebhookConfiguration struct {
-	Host    string `json:"host"`
-	Timeout int    `json:"timeout"`
+	Host           string               `json:"host"`
+	Timeout        int                  `json:"timeout"`
+	RateLimit      RateLimitConfig      `json:"rateLimit"`
+	CircuitBreaker CircuitBreakerConfig `json:"circuitBreaker"`
+	Signing        SigningConfig        `json:"signing"`
+	Callbacks      CallbackConfig       `json:"callbacks"`
+	Auth           AuthConfig           `json:"auth"`
+	TLS            TLSConfig            `json:"tls"`
+	Request        RequestConfig        `json:"request"`
+	// Failover names the providers tried in order while this one is unavailable.
+	Failover []string `json:"failover"`
+}
+
+// ProvidersConfig names webhooks besides the default one, messages reach them through routing rules.
+type ProvidersConfig map[string]WebhookConfiguration
+
+// RoutingRuleConfig sends the messages it matches through Provider. Set criteria must all match,
+// within a list any entry matches.
+type RoutingRuleConfig struct {
+	Provider      string   `json:"provider"`
+	PhonePrefixes []string `json:"phonePrefixes"` // e.g. "+90"
+	Tags          []string `json:"tags"`
+	Priorities    []string `json:"priorities"`
+}
+
+type RoutingConfig struct {
+	Rules []RoutingRuleConfig `json:"rules"`
+	// Default delivers messages no rule matches, "default" (the webhook settings) when empty.
+	Default string `json:"default"`
+}
+
+type RetryConfig struct {
+	MaxAttempts int     `json:"maxAttempts"`
+	BaseDelay   int     `json:"baseDelay"` // in ms
+	MaxDelay    int     `json:"maxDelay"`  // in ms
+	Multiplier  float64 `json:"multiplier"`
+	// Jitter is the share of the delay randomized away, 0..1. It is a pointer so that an explicit 0
+	// disables jitter while a missing value falls back to the default.
+	Jitter *float64 `json:"jitter"`
+}
+
+type LeaderElectionConfig struct {
+	Enabled       bool  `json:"enabled"`
+	LockKey       int64 `json:"lockKey"`       // postgres advisory lock key, shared by all replicas
+	RetryInterval int   `json:"retryInterval"` // in ms
+}
+
+type SchedulerConfig struct {
+	BatchSize              int                  `json:"batchSize"`
+	Interval               int                  `json:"interval"`   // in ms
+	JobTimeout             int                  `json:"jobTimeout"` // in ms
+	Concurrency            int                  `json:"concurrency"`
+	PreserveRecipientOrder bool                 `json:"preserveRecipientOrder"`
+	PriorityAging          int                  `json:"priorityAging"`     // in ms
+	LeaseDuration          int                  `json:"leaseDuration"`     // in ms
+	LeaseReapInterval      int                  `json:"leaseReapInterval"` // in ms
+	LeaderElection         LeaderElectionConfig `json:"leaderElection"`
+}
+
+const (
+	SinkHTTP = "http"
+	SinkFile = "file"
+)
+
+type OutboxHTTPConfig struct {
+	URL     string        `json:"url"`
+	Timeout int           `json:"timeout"` // in ms
+	Signing SigningConfig `json:"signing"`
+}
+
+type OutboxFileConfig struct {
+	Path string `json:"path"`
+}
+
+type OutboxConfig struct {
+	Enabled    bool             `json:"enabled"`
+	Sink       string           `json:"sink"` // one of http, file
+	HTTP       OutboxHTTPConfig `json:"http"`
+	File       OutboxFileConfig `json:"file"`
+	BatchSize  int              `json:"batchSize"`
+	Interval   int              `json:"interval"`   // in ms
+	RetryDelay int              `json:"retryDelay"` // in ms
+	Retention  int              `json:"retention"`  // in ms, 0 keeps published events
+}
+
+type TemplatesConfig struct {
+	// DefaultLocale picks the variant for messages whose locale no variant matches, before the
+	// template's own content is used.
+	DefaultLocale string `json:"defaultLocale"`
 }
 
 type AppConfig struct {
 	WebhookConfig WebhookConfiguration `json:"webhook"`
+	Providers     ProvidersConfig      `json:"providers"`
+	Routing       RoutingConfig        `json:"routing"`
+	Retry         RetryConfig          `json:"retry"`
+	Scheduler     SchedulerConfig      `json:"scheduler"`
+	Outbox        OutboxConfig         `json:"outbox"`
+	Templates     TemplatesConfig      `json:"templates"`
 	Port          string               `json:"port"`
 	AppName       string               `json:"appName"`
 	TeamName      string               `json:"teamName"`
@@ -71,8 +268,108 @@ func Read() AppConfig {
 }
 
 func (appCfg *AppConfig) SetDefaults() {
-	if appCfg.WebhookConfig.Timeout == 0 {
-		appCfg.WebhookConfig.Timeout = defaultRemoteServiceTimeout
+	appCfg.WebhookConfig.setDefaults()
+	for name, provider := range appCfg.Providers {
+		provider.setDefaults()
+		appCfg.Providers[name] = provider
 	}
 
+	if appCfg.Retry.MaxAttempts == 0 {
+		appCfg.Retry.MaxAttempts = defaultRetryMaxAttempts
+	}
+
+	if appCfg.Retry.BaseDelay == 0 {
+		appCfg.Retry.BaseDelay = defaultRetryBaseDelay
+	}
+
+	if appCfg.Retry.MaxDelay == 0 {
+		appCfg.Retry.MaxDelay = defaultRetryMaxDelay
+	}
+
+	if appCfg.Retry.Multiplier == 0 {
+		appCfg.Retry.Multiplier = defaultRetryMultiplier
+	}
+
+	if appCfg.Retry.Jitter == nil {
+		jitter := defaultRetryJitter
+		appCfg.Retry.Jitter = &jitter
+	}
+
+	if appCfg.Scheduler.BatchSize == 0 {
+		appCfg.Scheduler.BatchSize = defaultSchedulerBatchSize
+	}
+
+	if appCfg.Scheduler.Interval == 0 {
+		appCfg.Scheduler.Interval = defaultSchedulerInterval
+	}
+
+	if appCfg.Scheduler.JobTimeout == 0 {
+		appCfg.Scheduler.JobTimeout = defaultSchedulerJobTimeout
+	}
+
+	if appCfg.Scheduler.Concurrency == 0 {
+		appCfg.Scheduler.Concurrency = defaultSchedulerConcurrency
+	}
+
+	if appCfg.Scheduler.PriorityAging == 0 {
+		appCfg.Scheduler.PriorityAging = defaultSchedulerPriorityAging
+	}
+
+	if appCfg.Scheduler.LeaseDuration == 0 {
+		appCfg.Scheduler.LeaseDuration = defaultLeaseDuration
+	}
+
+	if appCfg.Scheduler.LeaseReapInterval == 0 {
+		appCfg.Scheduler.LeaseReapInterval = defaultLeaseReapInterval
+	}
+
+	if appCfg.Scheduler.LeaderElection.RetryInterval == 0 {
+		appCfg.Scheduler.LeaderElection.RetryInterval = defaultLeaderElectionRetryInterval
+	}
+
+	if appCfg.Scheduler.LeaderElection.LockKey == 0 {
+		appCfg.Scheduler.LeaderElection.LockKey = lockKeyFor(appCfg.AppName)
+	}
+
+	if appCfg.Outbox.BatchSize == 0 {
+		appCfg.Outbox.BatchSize = defaultOutboxBatchSize
+	}
+
+	if appCfg.Outbox.Interval == 0 {
+		appCfg.Outbox.Interval = defaultOutboxInterval
+	}
+
+	if appCfg.Outbox.RetryDelay == 0 {
+		appCfg.Outbox.RetryDelay = defaultOutboxRetryDelay
+	}
+
+	if appCfg.Outbox.HTTP.Timeout == 0 {
+		appCfg.Outbox.HTTP.Timeout = defaultOutboxHTTPTimeout
+	}
+
+	if appCfg.Templates.DefaultLocale == "" {
+		appCfg.Templates.DefaultLocale = defaultTemplatesLocale
+	}
+
+}
+
+func (webhookCfg *WebhookConfiguration) setDefaults() {
+	if webhookCfg.Timeout == 0 {
+		webhookCfg.Timeout = defaultRemoteServiceTimeout
+	}
+
+	if webhookCfg.CircuitBreaker.FailureThreshold == 0 {
+		webhookCfg.CircuitBreaker.FailureThreshold = defaultCircuitBreakerFailureThreshold
+	}
+
+	if webhookCfg.CircuitBreaker.CoolDown == 0 {
+		webhookCfg.CircuitBreaker.CoolDown = defaultCircuitBreakerCoolDown
+	}
+}
+
+// lockKeyFor derives a stable advisory lock key from the app name so replicas agree on it without configuration.
+func lockKeyFor(appName string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(appName))
+	return int64(hash.Sum64())
 }
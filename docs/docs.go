@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/_monitoring/health": {
             "get": {
-                "description": "Check if the message scheduler service is running",
+                "description": "Check if the message scheduler service is running, whether this instance is the scheduler leader and the state of the webhook circuit breakers",
                 "produces": [
                     "application/json"
                 ],
@@ -35,6 +35,486 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/scheduler-settings": {
+            "get": {
+                "description": "Return the batch size, interval, job timeout, dispatch concurrency and priority aging currently used by the message processing job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get Scheduler Settings",
+                "responses": {
+                    "200": {
+                        "description": "Current scheduler settings",
+                        "schema": {
+                            "$ref": "#/definitions/response.SchedulerSettingsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Stored settings could not be loaded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the batch size, interval, job timeout, dispatch concurrency or priority aging without a restart. The change is stored for all instances and applied by the one running the scheduler on its next tick.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update Scheduler Settings",
+                "parameters": [
+                    {
+                        "description": "Settings to change",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateSchedulerSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated scheduler settings",
+                        "schema": {
+                            "$ref": "#/definitions/response.SchedulerSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settings",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/callbacks/delivery/{provider}": {
+            "post": {
+                "description": "Called by the provider to report whether a sent message was delivered. The body must be signed with one of the provider's callback secrets in the X-Webhook-Signature header. Receipts are matched by the message id the provider's webhook returned; repeated or out-of-order receipts are accepted without changing the message. Without a provider in the path the receipt is for the default provider.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "callbacks"
+                ],
+                "summary": "Delivery Receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider that sent the message",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the body, t=\u003cunix time\u003e,v1=\u003chex HMAC-SHA256\u003e",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Delivery receipt",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.DeliveryReceiptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message status after the receipt",
+                        "schema": {
+                            "$ref": "#/definitions/response.DeliveryReceiptResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid receipt",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid signature",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No message with this id, retry later",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Message can not take a receipt yet, retry later",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages": {
+            "get": {
+                "description": "List messages newest first, narrowed by any combination of filters. Follow nextCursor to fetch the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Search Messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses, e.g. failed,retrying",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recipient phone number",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message id returned by the webhook",
+                        "name": "remoteMessageId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags the message must all carry, repeated or comma-separated",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 50, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Messages matching the filters",
+                        "schema": {
+                            "$ref": "#/definitions/response.ListMessagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Enqueue a new message to be sent by the scheduler, right away or once its sendAt time has come. Give either content or a templateId with the variables its placeholders need.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Create Message",
+                "parameters": [
+                    {
+                        "description": "Message to enqueue",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Message enqueued successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.CreateMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/batch": {
+            "post": {
+                "description": "Enqueue many messages at once. Accepts a JSON array or an NDJSON body (Content-Type: application/x-ndjson) and reports acceptance or the validation error for every item.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Create Messages In Batch",
+                "parameters": [
+                    {
+                        "description": "Messages to enqueue",
+                        "name": "messages",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/request.CreateMessageRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-item results",
+                        "schema": {
+                            "$ref": "#/definitions/response.CreateMessagesBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed batch",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/{id}": {
+            "get": {
+                "description": "Retrieve a message together with the history of its status and content changes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Get Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message and its history, oldest first",
+                        "schema": {
+                            "$ref": "#/definitions/response.MessageDetailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel a message that has not been picked up for sending yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Cancel Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expected message version",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancelled message",
+                        "schema": {
+                            "$ref": "#/definitions/response.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Message is already being sent, sent or changed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the send time, content or priority of a message that has not been picked up for sending yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Update Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "changes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated message",
+                        "schema": {
+                            "$ref": "#/definitions/response.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid changes",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Message is already being sent, sent or changed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/sent-messages": {
             "get": {
                 "description": "Retrieve sent messages with optional limit",
@@ -63,55 +543,885 @@ const docTemplate = `{
                 }
             }
         },
-        "/start-send-message": {
-            "post": {
-                "description": "Send a message via webhook and return the webhook response",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "messages"
-                ],
-                "summary": "Start Send Message",
-                "responses": {}
+        "/start-send-message": {
+            "post": {
+                "description": "Send a message via webhook and return the webhook response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Start Send Message",
+                "responses": {}
+            }
+        },
+        "/stop-message-sender": {
+            "post": {
+                "description": "Stop the currently running message-sending scheduler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Stop Message Sender",
+                "responses": {
+                    "200": {
+                        "description": "Scheduler stopped successfully",
+                        "schema": {
+                            "$ref": "#/definitions/response.StopSchedulerResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates": {
+            "get": {
+                "description": "List the current version of every template, ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List Templates",
+                "responses": {
+                    "200": {
+                        "description": "Templates",
+                        "schema": {
+                            "$ref": "#/definitions/response.ListTemplatesResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Store a message template as version 1. {{variable}} placeholders in the content are filled from the variables of every message enqueued with its templateId. Variants translate the content for other locales; a message gets the variant of its locale, then of the language alone, e.g. tr-TR then tr, and the content otherwise.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Create Template",
+                "parameters": [
+                    {
+                        "description": "Template to create",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CreateTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created template",
+                        "schema": {
+                            "$ref": "#/definitions/response.TemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Template name already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}": {
+            "get": {
+                "description": "Retrieve the current or an earlier version of a template",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Get Template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to retrieve (default: current)",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Template",
+                        "schema": {
+                            "$ref": "#/definitions/response.TemplateResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template or version not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Store new content and variants as the next version of a template, variants of the previous version are kept unless variants are given. Messages waiting to be sent are rendered with it when they are sent, so it must render each of them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Update Template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New content",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.UpdateTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New version of the template",
+                        "schema": {
+                            "$ref": "#/definitions/response.TemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Version does not render messages waiting to be sent",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a template with all its versions. Messages already sent from it keep their content.",
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Delete Template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Template deleted"
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Template is used by messages waiting to be sent",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}/versions": {
+            "get": {
+                "description": "List every version of a template, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List Template Versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Versions of the template",
+                        "schema": {
+                            "$ref": "#/definitions/response.TemplateVersionsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "request.CreateMessageRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Hello, World!"
+                },
+                "locale": {
+                    "description": "Locale is the recipient's language tag, it picks the matching variant of the template.",
+                    "type": "string",
+                    "example": "tr-TR"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "critical",
+                        "high",
+                        "normal",
+                        "bulk"
+                    ],
+                    "example": "normal"
+                },
+                "sendAt": {
+                    "description": "RFC 3339, omit to send right away",
+                    "type": "string",
+                    "example": "2025-01-02T09:00:00+03:00"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "campaign-42"
+                    ]
+                },
+                "templateId": {
+                    "description": "TemplateID renders the content from a stored template instead, filling its placeholders from Variables.",
+                    "type": "string",
+                    "example": "7"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "request.CreateTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "{{variable}} placeholders",
+                    "type": "string",
+                    "example": "Hi {{name}}, your code is {{code}}"
+                },
+                "locale": {
+                    "description": "Locale is the language of Content, Variants hold the content for other locales.",
+                    "type": "string",
+                    "example": "en"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "otp"
+                },
+                "variants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "request.DeliveryReceiptRequest": {
+            "type": "object",
+            "properties": {
+                "messageId": {
+                    "description": "id returned by the webhook when the message was sent",
+                    "type": "string",
+                    "example": "whatsapp-msg-123"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "recipient unreachable"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "delivered",
+                        "read",
+                        "failed"
+                    ],
+                    "example": "delivered"
+                },
+                "timestamp": {
+                    "description": "RFC 3339, defaults to the time of the callback",
+                    "type": "string",
+                    "example": "2025-01-02T06:00:05Z"
+                }
+            }
+        },
+        "request.UpdateMessageRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Hello again!"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "critical",
+                        "high",
+                        "normal",
+                        "bulk"
+                    ],
+                    "example": "high"
+                },
+                "sendAt": {
+                    "description": "RFC 3339",
+                    "type": "string",
+                    "example": "2025-01-02T10:00:00+03:00"
+                },
+                "version": {
+                    "description": "rejects the change when the message has moved on",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "request.UpdateSchedulerSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "batchSize": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "concurrency": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "interval": {
+                    "description": "in ms",
+                    "type": "integer",
+                    "example": 60000
+                },
+                "jobTimeout": {
+                    "description": "in ms",
+                    "type": "integer",
+                    "example": 30000
+                },
+                "preserveRecipientOrder": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "priorityAging": {
+                    "description": "in ms",
+                    "type": "integer",
+                    "example": 300000
+                }
+            }
+        },
+        "request.UpdateTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "{{code}} is your code, {{name}}"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "variants": {
+                    "description": "Variants replace those of the previous version, which are kept when Variants is left out.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "response.BatchMessageResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "phone must be in E.164 format, e.g. +905551234567"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "string",
+                    "example": "accepted"
+                }
+            }
+        },
+        "response.CreateMessageResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "normal"
+                },
+                "sendAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "unsent"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "response.CreateMessagesBatchResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.BatchMessageResult"
+                    }
+                }
+            }
+        },
+        "response.DeliveryReceiptResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "delivered"
+                }
+            }
+        },
+        "response.GetSentMessagesResponse": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.SentMessageResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.ListMessagesResponse": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.MessageResponse"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor fetches the following page, it is empty on the last one.",
+                    "type": "string",
+                    "example": "eyJjIjoiMjAyNS0wMS0wMVQxMDowMDowMFoiLCJpIjoiNDIifQ"
+                }
+            }
+        },
+        "response.ListTemplatesResponse": {
+            "type": "object",
+            "properties": {
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TemplateResponse"
+                    }
+                }
             }
         },
-        "/stop-message-sender": {
-            "post": {
-                "description": "Stop the currently running message-sending scheduler",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "messages"
-                ],
-                "summary": "Stop Message Sender",
-                "responses": {
-                    "200": {
-                        "description": "Scheduler stopped successfully",
-                        "schema": {
-                            "$ref": "#/definitions/response.StopSchedulerResponse"
-                        }
+        "response.MessageDetailResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "content": {
+                    "type": "string",
+                    "example": "Hello, World!"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-01-01T10:00:00Z"
+                },
+                "deliveryError": {
+                    "type": "string",
+                    "example": "recipient unreachable"
+                },
+                "deliveryReportedAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:05Z"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.MessageHistoryResponse"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "lastError": {
+                    "type": "string",
+                    "example": "webhook returned 503"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "tr-TR"
+                },
+                "nextAttemptAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:01:00Z"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "normal"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "remoteMessageId": {
+                    "type": "string",
+                    "example": "whatsapp-msg-123"
+                },
+                "sendAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:00Z"
+                },
+                "sentAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:03Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "unsent"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "campaign-42"
+                    ]
+                },
+                "templateId": {
+                    "description": "TemplateID is set on messages enqueued from a template, TemplateVersion is the version rendered last.",
+                    "type": "string",
+                    "example": "7"
+                },
+                "templateVersion": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-01-01T10:05:00Z"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     }
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
-        }
-    },
-    "definitions": {
-        "response.GetSentMessagesResponse": {
+        },
+        "response.MessageHistoryResponse": {
             "type": "object",
             "properties": {
-                "messages": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "content": {
+                    "type": "string",
+                    "example": "Hello, World!"
+                },
+                "lastError": {
+                    "type": "string",
+                    "example": "webhook returned 503"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "normal"
+                },
+                "recordedAt": {
+                    "type": "string",
+                    "example": "2025-01-01T10:00:00Z"
+                },
+                "remoteMessageId": {
+                    "type": "string",
+                    "example": "whatsapp-msg-123"
+                },
+                "sendAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "unsent"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "response.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "content": {
+                    "type": "string",
+                    "example": "Hello, World!"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-01-01T10:00:00Z"
+                },
+                "deliveryError": {
+                    "type": "string",
+                    "example": "recipient unreachable"
+                },
+                "deliveryReportedAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "lastError": {
+                    "type": "string",
+                    "example": "webhook returned 503"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "tr-TR"
+                },
+                "nextAttemptAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:01:00Z"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "normal"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "remoteMessageId": {
+                    "type": "string",
+                    "example": "whatsapp-msg-123"
+                },
+                "sendAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:00Z"
+                },
+                "sentAt": {
+                    "type": "string",
+                    "example": "2025-01-02T06:00:03Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "unsent"
+                },
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/response.SentMessageResponse"
+                        "type": "string"
+                    },
+                    "example": [
+                        "campaign-42"
+                    ]
+                },
+                "templateId": {
+                    "description": "TemplateID is set on messages enqueued from a template, TemplateVersion is the version rendered last.",
+                    "type": "string",
+                    "example": "7"
+                },
+                "templateVersion": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-01-01T10:05:00Z"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     }
                 },
-                "total": {
-                    "type": "integer"
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "response.SchedulerSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "batchSize": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "concurrency": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "interval": {
+                    "description": "in ms",
+                    "type": "integer",
+                    "example": 120000
+                },
+                "jobTimeout": {
+                    "description": "in ms",
+                    "type": "integer",
+                    "example": 30000
+                },
+                "preserveRecipientOrder": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "priorityAging": {
+                    "description": "in ms",
+                    "type": "integer",
+                    "example": 300000
                 }
             }
         },
@@ -134,10 +1444,22 @@ const docTemplate = `{
                     "type": "string",
                     "example": "+905551234567"
                 },
+                "priority": {
+                    "type": "string",
+                    "example": "normal"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "default"
+                },
                 "remoteMessageId": {
                     "type": "string",
                     "example": "whatsapp-msg-123"
                 },
+                "sendAt": {
+                    "type": "string",
+                    "example": "2023-10-01T10:00:00Z"
+                },
                 "sentAt": {
                     "type": "string",
                     "example": "2023-10-01T10:05:00Z"
@@ -165,6 +1487,84 @@ const docTemplate = `{
                 }
             }
         },
+        "response.TemplateResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Hi {{name}}, your code is {{code}}"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-01-01T10:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "otp"
+                },
+                "updatedAt": {
+                    "description": "UpdatedAt is when this version was created.",
+                    "type": "string",
+                    "example": "2025-01-01T10:05:00Z"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "name",
+                        "code"
+                    ]
+                },
+                "variants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "response.TemplateVersionsResponse": {
+            "type": "object",
+            "properties": {
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.TemplateResponse"
+                    }
+                }
+            }
+        },
+        "server.CircuitBreakerHealth": {
+            "type": "object",
+            "properties": {
+                "consecutiveFailures": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "openedAt": {
+                    "type": "string",
+                    "example": "2025-01-01T12:00:00Z"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "closed"
+                }
+            }
+        },
         "server.HealthResponse": {
             "type": "object",
             "properties": {
@@ -172,9 +1572,55 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Message scheduler alive!"
                 },
+                "scheduler": {
+                    "$ref": "#/definitions/server.SchedulerHealth"
+                },
                 "status": {
                     "type": "string",
                     "example": "ok"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/server.WebhookHealth"
+                }
+            }
+        },
+        "server.ProviderHealth": {
+            "type": "object",
+            "properties": {
+                "circuitBreaker": {
+                    "$ref": "#/definitions/server.CircuitBreakerHealth"
+                }
+            }
+        },
+        "server.SchedulerHealth": {
+            "type": "object",
+            "properties": {
+                "leader": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "leaderElection": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "running": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "server.WebhookHealth": {
+            "type": "object",
+            "properties": {
+                "circuitBreaker": {
+                    "$ref": "#/definitions/server.CircuitBreakerHealth"
+                },
+                "providers": {
+                    "description": "Providers is set when messages are routed over several providers.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/server.ProviderHealth"
+                    }
                 }
             }
         }
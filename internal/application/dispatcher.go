@@ -0,0 +1,138 @@
+package application
+
+import (
+	"context"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/log"
+	"sync"
+)
+
+type dispatchOutcome int
+
+const (
+	outcomeSucceeded dispatchOutcome = iota
+	outcomeFailed
+	outcomeSkipped
+)
+
+// ProcessResult summarizes one processing tick. Skipped messages were claimed but not attempted, e.g.
+// because the tick timed out, the webhook quota ran out or the circuit breaker opened; they go back
+// to the queue without using up an attempt.
+type ProcessResult struct {
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+func (r *ProcessResult) add(outcome dispatchOutcome) {
+	switch outcome {
+	case outcomeSucceeded:
+		r.Succeeded++
+	case outcomeFailed:
+		r.Failed++
+	case outcomeSkipped:
+		r.Skipped++
+	}
+}
+
+// dispatch sends messages with at most Concurrency parallel webhook calls. With PreserveRecipientOrder
+// all messages of a recipient are handled by the same worker in claim order, and the rest of them are
+// skipped once one fails. The claim then holds them back until the failed one is sent or given up
+// on, so a later message never overtakes an earlier one across ticks either.
+func (is *MessageSendService) dispatch(ctx context.Context, messages []*entity.MessagesEntity) ProcessResult {
+	settings := is.SchedulerSettings()
+	groups := groupMessages(messages, settings.PreserveRecipientOrder)
+
+	workers := min(max(settings.Concurrency, 1), len(groups))
+
+	queue := make(chan []*entity.MessagesEntity)
+	outcomes := make(chan dispatchOutcome, len(messages))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				is.dispatchGroup(ctx, group, outcomes)
+			}
+		}()
+	}
+
+	for _, group := range groups {
+		queue <- group
+	}
+	close(queue)
+
+	wg.Wait()
+	close(outcomes)
+
+	var result ProcessResult
+	for outcome := range outcomes {
+		result.add(outcome)
+	}
+	return result
+}
+
+func (is *MessageSendService) dispatchGroup(ctx context.Context, group []*entity.MessagesEntity, outcomes chan<- dispatchOutcome) {
+	blocked := false
+
+	for _, message := range group {
+		if blocked || ctx.Err() != nil {
+			is.releaseMessage(ctx, message)
+			outcomes <- outcomeSkipped
+			continue
+		}
+
+		outcome := is.sendMessage(ctx, message)
+		outcomes <- outcome
+
+		// groups only hold more than one message when recipient order must be preserved
+		if outcome != outcomeSucceeded {
+			blocked = true
+		}
+	}
+}
+
+// releaseMessage hands a claimed but unattempted message back to the queue. When the context is
+// already done the save fails and the lease reaper releases the message once its lease expires.
+func (is *MessageSendService) releaseMessage(ctx context.Context, message *entity.MessagesEntity) {
+	message.Status = status.UNSENT
+	message.LeaseOwner = ""
+	message.LeaseExpiresAt = ""
+
+	saved, err := is.saveClaimed(ctx, message)
+	if err != nil {
+		log.Logger.Warn().Err(err).Str("message_id", message.Id).Msg("Failed to release skipped message, leaving it to the lease reaper")
+		return
+	}
+	if !saved {
+		return
+	}
+
+	log.Logger.Info().Str("message_id", message.Id).Msg("Released skipped message")
+}
+
+func groupMessages(messages []*entity.MessagesEntity, byRecipient bool) [][]*entity.MessagesEntity {
+	groups := make([][]*entity.MessagesEntity, 0, len(messages))
+
+	if !byRecipient {
+		for _, message := range messages {
+			groups = append(groups, []*entity.MessagesEntity{message})
+		}
+		return groups
+	}
+
+	positions := make(map[string]int)
+	for _, message := range messages {
+		position, ok := positions[message.Phone]
+		if !ok {
+			position = len(groups)
+			positions[message.Phone] = position
+			groups = append(groups, nil)
+		}
+		groups[position] = append(groups[position], message)
+	}
+	return groups
+}
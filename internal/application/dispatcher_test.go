@@ -0,0 +1,260 @@
+package application
+
+import (
+	"context"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/client/webhook"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/mocks"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessUnsentMessages_BoundedConcurrency(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	settings := DefaultSchedulerSettings()
+	settings.Concurrency = 3
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler, WithSchedulerSettings(settings))
+
+	ctx := context.Background()
+	messages := make([]*entity.MessagesEntity, 10)
+	for i := range messages {
+		messages[i] = createTestMessage(status.UNSENT)
+	}
+
+	var inFlight, peak atomic.Int32
+	mockRepo.On("ClaimMessages", ctx, claimLimit(10)).Return(messages, nil)
+	mockWebhook.On("SendMessage", ctx, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		current := inFlight.Add(1)
+		for {
+			observed := peak.Load()
+			if current <= observed || peak.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+	}).Return(&webhook.WebhookResponse{MessageID: "webhook-msg-123"}, nil)
+	mockRepo.On("SaveClaimed", ctx, mock.Anything, mock.Anything).Return(true, nil)
+
+	result, err := service.ProcessUnsentMessages(ctx, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Succeeded: 10}, result)
+	assert.Equal(t, int32(3), peak.Load())
+	mockWebhook.AssertNumberOfCalls(t, "SendMessage", 10)
+}
+
+func TestProcessUnsentMessages_PreserveRecipientOrderSkipsAfterFailure(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	settings := DefaultSchedulerSettings()
+	settings.PreserveRecipientOrder = true
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler, WithSchedulerSettings(settings))
+
+	ctx := context.Background()
+	first := createTestMessage(status.UNSENT)
+	first.Content = "first"
+	second := createTestMessage(status.UNSENT)
+	second.Content = "second"
+	other := createTestMessage(status.UNSENT)
+	other.Phone = "+905559876543"
+
+	// later messages of a recipient whose earlier one is retrying are held back by the claim itself
+	mockRepo.On("ClaimMessages", ctx, mock.MatchedBy(func(claim repository.ClaimOptions) bool {
+		return claim.Limit == 3 && claim.PreserveRecipientOrder
+	})).Return([]*entity.MessagesEntity{first, second, other}, nil)
+	mockWebhook.On("SendMessage", ctx, first, mock.Anything).Return(nil, port.ValidationError{Msg: "invalid recipient"}).Once()
+	mockWebhook.On("SendMessage", ctx, other, mock.Anything).Return(&webhook.WebhookResponse{MessageID: "webhook-msg-123"}, nil).Once()
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Id == first.Id && msg.Status == status.FAILED
+	}), mock.Anything).Return(true, nil).Once()
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Id == second.Id && msg.Status == status.UNSENT && msg.Attempts == 0 && msg.LeaseOwner == ""
+	}), mock.Anything).Return(true, nil).Once()
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Id == other.Id && msg.Status == status.SENT
+	}), mock.Anything).Return(true, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Succeeded: 1, Failed: 1, Skipped: 1}, result)
+	mockRepo.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_SkipsWhenContextDone(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	messages := []*entity.MessagesEntity{
+		createTestMessage(status.UNSENT),
+		createTestMessage(status.RETRYING),
+	}
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(2)).Run(func(mock.Arguments) {
+		cancel()
+	}).Return(messages, nil)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.UNSENT
+	}), mock.Anything).Return(false, context.Canceled).Twice()
+
+	result, err := service.ProcessUnsentMessages(ctx, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Skipped: 2}, result)
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGroupMessages(t *testing.T) {
+	a1 := &entity.MessagesEntity{Id: "1", Phone: "+905551111111"}
+	b1 := &entity.MessagesEntity{Id: "2", Phone: "+905552222222"}
+	a2 := &entity.MessagesEntity{Id: "3", Phone: "+905551111111"}
+	messages := []*entity.MessagesEntity{a1, b1, a2}
+
+	assert.Equal(t, [][]*entity.MessagesEntity{{a1}, {b1}, {a2}}, groupMessages(messages, false))
+	assert.Equal(t, [][]*entity.MessagesEntity{{a1, a2}, {b1}}, groupMessages(messages, true))
+}
+
+type quotaAwareWebhookMock struct {
+	mocks.WebhookClientMock
+	available int
+}
+
+func (c *quotaAwareWebhookMock) Available(ctx context.Context) int {
+	return c.available
+}
+
+func TestProcessUnsentMessages_QuotaExhaustedSkipsClaim(t *testing.T) {
+	mockWebhook := &quotaAwareWebhookMock{available: 0}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	result, err := service.ProcessUnsentMessages(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{}, result)
+	mockRepo.AssertNotCalled(t, "ClaimMessages", mock.Anything, mock.Anything)
+}
+
+func TestProcessUnsentMessages_ClaimsNoMoreThanQuota(t *testing.T) {
+	mockWebhook := &quotaAwareWebhookMock{available: 2}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+	mockRepo.On("ClaimMessages", ctx, claimLimit(2)).Return([]*entity.MessagesEntity{}, nil).Once()
+
+	_, err := service.ProcessUnsentMessages(ctx, 5)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+type routeAwareWebhookMock struct {
+	quotaAwareWebhookMock
+	routing webhook.Routing
+}
+
+func (c *routeAwareWebhookMock) Routing(ctx context.Context) webhook.Routing {
+	return c.routing
+}
+
+func TestProcessUnsentMessages_ClaimsPerProviderQuota(t *testing.T) {
+	mockWebhook := &routeAwareWebhookMock{
+		quotaAwareWebhookMock: quotaAwareWebhookMock{available: 2},
+		routing: webhook.Routing{
+			Rules:     []webhook.Rule{{Provider: "otp", Priorities: []priority.MessagePriority{priority.CRITICAL}}, {Provider: "turkey", PhonePrefixes: []string{"+90"}}},
+			Fallback:  webhook.DefaultProvider,
+			Available: map[string]int{"otp": 0, "turkey": 2},
+		},
+	}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(mockWebhook, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("ClaimMessages", ctx, mock.MatchedBy(func(claim repository.ClaimOptions) bool {
+		return claim.Limit == 2 && assert.ObjectsAreEqual(&repository.ClaimRoutes{
+			Rules: []repository.RouteRule{
+				{Provider: "otp", Priorities: []string{"critical"}},
+				{Provider: "turkey", PhonePrefixes: []string{"+90"}},
+			},
+			Fallback: webhook.DefaultProvider,
+			Quotas:   map[string]int{"otp": 0, "turkey": 2},
+		}, claim.Routes)
+	})).Return([]*entity.MessagesEntity{}, nil).Once()
+
+	_, err := service.ProcessUnsentMessages(ctx, 5)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_RateLimitedMessageIsReleased(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+	message := createTestMessage(status.RETRYING)
+	message.Attempts = 2
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, webhook.ErrRateLimited)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.UNSENT && msg.Attempts == 2 && msg.LastError == ""
+	}), mock.Anything).Return(true, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Skipped: 1}, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_OpenCircuitReleasesMessage(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, webhook.ErrCircuitOpen)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.UNSENT && msg.Attempts == 0
+	}), mock.Anything).Return(true, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Skipped: 1}, result)
+	mockRepo.AssertExpectations(t)
+}
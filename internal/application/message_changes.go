@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"time"
+)
+
+// MessageChanges lists the fields to change on a pending message, nil fields are kept. New content
+// replaces the template of a templated message. Version, when set, must match the current version of
+// the message, so clients do not overwrite changes they have not seen.
+type MessageChanges struct {
+	Content  *string
+	SendAt   *string
+	Priority *priority.MessagePriority
+	Version  int
+}
+
+// CancelMessage withdraws a message that has not been picked up for sending yet.
+func (is *MessageSendService) CancelMessage(ctx context.Context, id string, version int) (*entity.MessagesEntity, error) {
+	message, err := is.getPendingMessage(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	message.Status = status.CANCELLED
+
+	if err := is.updatePendingMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	log.Logger.Info().Str("message_id", message.Id).Msg("Message cancelled")
+	return message, nil
+}
+
+// UpdateMessage changes the content, send time or priority of a message that has not been picked up
+// for sending yet.
+func (is *MessageSendService) UpdateMessage(ctx context.Context, id string, changes MessageChanges) (*entity.MessagesEntity, error) {
+	message, err := is.getPendingMessage(ctx, id, changes.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.Content != nil {
+		message.Content = *changes.Content
+		message.TemplateId = ""
+		message.TemplateVersion = 0
+		message.Variables = nil
+	}
+	if changes.Priority != nil {
+		message.Priority = normalizePriority(*changes.Priority)
+	}
+	if changes.SendAt != nil {
+		message.SendAt = *changes.SendAt
+	}
+
+	if err := validateMessage(message); err != nil {
+		log.Logger.Warn().Err(err).Str("message_id", message.Id).Msg("Rejected invalid message changes")
+		return nil, err
+	}
+	message.SendAt = normalizeSendAt(message.SendAt, time.Now())
+
+	if err := is.updatePendingMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	log.Logger.Info().Str("message_id", message.Id).Int("version", message.Version).Msg("Message updated")
+	return message, nil
+}
+
+func (is *MessageSendService) getPendingMessage(ctx context.Context, id string, version int) (*entity.MessagesEntity, error) {
+	message, err := is.repo.GetMessage(ctx, id)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return nil, port.NotFoundError{Msg: fmt.Sprintf("message %s not found", id)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if !isPending(message.Status) {
+		return nil, port.ConflictError{Msg: fmt.Sprintf("message %s is %s and can no longer be changed", id, message.Status)}
+	}
+
+	if version != 0 && version != message.Version {
+		return nil, port.ConflictError{Msg: fmt.Sprintf("message %s has version %d, not %d", id, message.Version, version)}
+	}
+
+	return message, nil
+}
+
+// updatePendingMessage stores the changes unless the message was claimed or edited since it was read.
+func (is *MessageSendService) updatePendingMessage(ctx context.Context, message *entity.MessagesEntity) error {
+	updated, err := is.repo.UpdatePending(ctx, message)
+	if err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+
+	if !updated {
+		return port.ConflictError{Msg: fmt.Sprintf("message %s was changed or picked up for sending in the meantime", message.Id)}
+	}
+
+	return nil
+}
+
+func isPending(messageStatus status.MessageStatus) bool {
+	return messageStatus == status.UNSENT || messageStatus == status.RETRYING
+}
@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createPendingMessage() *entity.MessagesEntity {
+	message := createTestMessage(status.UNSENT)
+	message.Id = "42"
+	message.Priority = priority.NORMAL
+	message.SendAt = "2030-01-01T09:00:00Z"
+	message.Version = 3
+	return message
+}
+
+func TestCancelMessage_Success(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("GetMessage", ctx, "42").Return(createPendingMessage(), nil)
+	mockRepo.On("UpdatePending", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.CANCELLED && msg.Version == 3
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.MessagesEntity).Version = 4
+	}).Return(true, nil)
+
+	message, err := service.CancelMessage(ctx, "42", 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.CANCELLED, message.Status)
+	assert.Equal(t, 4, message.Version)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelMessage_NotFound(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("GetMessage", ctx, "42").Return(nil, repository.ErrMessageNotFound)
+
+	_, err := service.CancelMessage(ctx, "42", 0)
+
+	assert.ErrorAs(t, err, &port.NotFoundError{})
+}
+
+func TestCancelMessage_Conflicts(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  status.MessageStatus
+		version int
+	}{
+		{name: "being sent", status: status.SENDING},
+		{name: "already sent", status: status.SENT},
+		{name: "already cancelled", status: status.CANCELLED},
+		{name: "stale version", status: status.UNSENT, version: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &mocks.MessagesRepositoryMock{}
+			service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+			ctx := context.Background()
+			message := createPendingMessage()
+			message.Status = tc.status
+			mockRepo.On("GetMessage", ctx, "42").Return(message, nil)
+
+			_, err := service.CancelMessage(ctx, "42", tc.version)
+
+			assert.ErrorAs(t, err, &port.ConflictError{})
+			mockRepo.AssertNotCalled(t, "UpdatePending", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCancelMessage_ClaimedInTheMeantime(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("GetMessage", ctx, "42").Return(createPendingMessage(), nil)
+	mockRepo.On("UpdatePending", ctx, mock.Anything).Return(false, nil)
+
+	_, err := service.CancelMessage(ctx, "42", 0)
+
+	assert.ErrorAs(t, err, &port.ConflictError{})
+}
+
+func TestUpdateMessage_Success(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	content := "Moved to ten"
+	sendAt := "2030-01-01T13:00:00+03:00"
+	high := priority.MessagePriority("HIGH")
+
+	mockRepo.On("GetMessage", ctx, "42").Return(createPendingMessage(), nil)
+	mockRepo.On("UpdatePending", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Content == content && msg.SendAt == "2030-01-01T10:00:00Z" && msg.Priority == priority.HIGH &&
+			msg.Status == status.UNSENT && msg.Version == 3
+	})).Return(true, nil)
+
+	message, err := service.UpdateMessage(ctx, "42", MessageChanges{Content: &content, SendAt: &sendAt, Priority: &high})
+
+	assert.NoError(t, err)
+	assert.Equal(t, content, message.Content)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateMessage_KeepsOmittedFields(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	pending := createPendingMessage()
+	originalContent := pending.Content
+	bulk := priority.BULK
+
+	mockRepo.On("GetMessage", ctx, "42").Return(pending, nil)
+	mockRepo.On("UpdatePending", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Content == originalContent && msg.SendAt == "2030-01-01T09:00:00Z" && msg.Priority == priority.BULK
+	})).Return(true, nil)
+
+	_, err := service.UpdateMessage(ctx, "42", MessageChanges{Priority: &bulk, Version: 3})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateMessage_ValidationError(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	sendAt := "tomorrow"
+
+	mockRepo.On("GetMessage", ctx, "42").Return(createPendingMessage(), nil)
+
+	_, err := service.UpdateMessage(ctx, "42", MessageChanges{SendAt: &sendAt})
+
+	assert.ErrorAs(t, err, &port.ValidationError{})
+	mockRepo.AssertNotCalled(t, "UpdatePending", mock.Anything, mock.Anything)
+}
+
+func TestUpdateMessage_RepositoryError(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("GetMessage", ctx, "42").Return(nil, fmt.Errorf("database error"))
+
+	_, err := service.UpdateMessage(ctx, "42", MessageChanges{})
+
+	assert.Error(t, err)
+	assert.NotErrorAs(t, err, &port.NotFoundError{})
+}
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"message-scheduler/signature"
+	"strings"
+	"time"
+)
+
+// ReceiptStatus is the outcome reported by the provider in a delivery receipt.
+type ReceiptStatus string
+
+const (
+	ReceiptDelivered ReceiptStatus = "delivered"
+	ReceiptRead      ReceiptStatus = "read" // implies delivered
+	ReceiptFailed    ReceiptStatus = "failed"
+)
+
+// DeliveryReceipt is posted by the provider once it knows whether a message reached the recipient.
+// RemoteMessageID is the id the provider's webhook returned when the message was sent, Timestamp the
+// provider's RFC 3339 time of the outcome.
+type DeliveryReceipt struct {
+	Provider        string
+	RemoteMessageID string
+	Status          ReceiptStatus
+	Timestamp       string
+	Reason          string
+}
+
+// WithCallbackVerifiers authenticates delivery receipts: the callbacks of a provider must be signed
+// with one of the secrets of its verifier, providers without a verifier can not post receipts.
+func WithCallbackVerifiers(verifiers map[string]*signature.Verifier) Option {
+	return func(is *MessageSendService) {
+		is.callbacks = verifiers
+	}
+}
+
+// VerifyDeliveryCallback checks that the raw callback body was signed by the provider.
+func (is *MessageSendService) VerifyDeliveryCallback(provider string, signatureHeader string, body []byte) error {
+	verifier, ok := is.callbacks[provider]
+	if !ok {
+		log.Logger.Warn().Str("provider", provider).Msg("Rejected delivery callback of a provider without callback secrets")
+		return port.UnauthorizedError{Msg: fmt.Sprintf("provider %s does not accept delivery callbacks", provider)}
+	}
+
+	if err := verifier.Verify(signatureHeader, body); err != nil {
+		log.Logger.Warn().Err(err).Str("provider", provider).Msg("Rejected delivery callback with an invalid signature")
+		return port.UnauthorizedError{Msg: "invalid callback signature", WrappedErr: err}
+	}
+	return nil
+}
+
+// RecordDeliveryReceipt moves a sent message to DELIVERED or UNDELIVERED. Providers retry receipts
+// and do not guarantee their order, so a receipt not newer than the one already recorded, or a read
+// receipt for a message already known to be delivered, leaves the message unchanged.
+func (is *MessageSendService) RecordDeliveryReceipt(ctx context.Context, receipt DeliveryReceipt) (*entity.MessagesEntity, error) {
+	reportedAt, err := validateReceipt(&receipt)
+	if err != nil {
+		log.Logger.Warn().Err(err).Str("remote_message_id", receipt.RemoteMessageID).Msg("Rejected invalid delivery receipt")
+		return nil, err
+	}
+
+	message, err := is.repo.GetMessageByRemoteID(ctx, receipt.Provider, receipt.RemoteMessageID)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return nil, port.NotFoundError{Msg: fmt.Sprintf("no message of provider %s with remote id %s", receipt.Provider, receipt.RemoteMessageID)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if !isDeliverable(message.Status) {
+		return nil, port.ConflictError{Msg: fmt.Sprintf("message %s is %s and can not take a delivery receipt", message.Id, message.Status)}
+	}
+
+	if supersededReceipt(message, receipt, reportedAt) {
+		log.Logger.Info().Str("message_id", message.Id).Str("receipt", string(receipt.Status)).Msg("Ignored superseded delivery receipt")
+		return message, nil
+	}
+
+	message.DeliveryReportedAt = reportedAt.UTC().Format(time.RFC3339Nano)
+	if receipt.Status == ReceiptFailed {
+		message.Status = status.UNDELIVERED
+		message.DeliveryError = receipt.Reason
+	} else {
+		message.Status = status.DELIVERED
+		message.DeliveryError = ""
+	}
+
+	updated, err := is.repo.RecordDelivery(ctx, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record delivery: %w", err)
+	}
+	if !updated {
+		return nil, port.ConflictError{Msg: fmt.Sprintf("message %s was changed in the meantime", message.Id)}
+	}
+
+	log.Logger.Info().Str("message_id", message.Id).Str("status", string(message.Status)).Msg("Delivery receipt recorded")
+	return message, nil
+}
+
+// validateReceipt normalizes the receipt status and returns its timestamp in UTC, defaulting to now
+// when the provider sent none.
+func validateReceipt(receipt *DeliveryReceipt) (time.Time, error) {
+	receipt.RemoteMessageID = strings.TrimSpace(receipt.RemoteMessageID)
+	if receipt.RemoteMessageID == "" {
+		return time.Time{}, port.ValidationError{Msg: "messageId must not be empty"}
+	}
+
+	receipt.Status = ReceiptStatus(strings.ToLower(strings.TrimSpace(string(receipt.Status))))
+	switch receipt.Status {
+	case ReceiptDelivered, ReceiptRead, ReceiptFailed:
+	default:
+		return time.Time{}, port.ValidationError{Msg: "status must be one of delivered, read or failed"}
+	}
+
+	if receipt.Timestamp == "" {
+		return time.Now().UTC(), nil
+	}
+
+	reportedAt, err := time.Parse(time.RFC3339, receipt.Timestamp)
+	if err != nil {
+		return time.Time{}, port.ValidationError{Msg: "timestamp must be an RFC 3339 timestamp, e.g. 2025-01-02T09:00:00+03:00"}
+	}
+	return reportedAt.UTC(), nil
+}
+
+func supersededReceipt(message *entity.MessagesEntity, receipt DeliveryReceipt, reportedAt time.Time) bool {
+	if receipt.Status == ReceiptRead && message.Status == status.DELIVERED {
+		return true
+	}
+
+	if message.DeliveryReportedAt == "" {
+		return false
+	}
+
+	recorded, err := time.Parse(time.RFC3339Nano, message.DeliveryReportedAt)
+	return err == nil && !reportedAt.After(recorded)
+}
+
+func isDeliverable(messageStatus status.MessageStatus) bool {
+	return messageStatus == status.SENT || messageStatus == status.DELIVERED || messageStatus == status.UNDELIVERED
+}
@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/mocks"
+	"message-scheduler/signature"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createSentMessage() *entity.MessagesEntity {
+	message := createTestMessage(status.SENT)
+	message.Id = "42"
+	message.RemoteMessageId = "remote-42"
+	message.Version = 2
+	return message
+}
+
+func TestRecordDeliveryReceipt_Delivered(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("GetMessageByRemoteID", ctx, "default", "remote-42").Return(createSentMessage(), nil)
+	mockRepo.On("RecordDelivery", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.DELIVERED && msg.DeliveryReportedAt == "2025-01-02T06:00:05Z" && msg.Version == 2
+	})).Return(true, nil).Once()
+
+	message, err := service.RecordDeliveryReceipt(ctx, DeliveryReceipt{
+		Provider:        "default",
+		RemoteMessageID: "remote-42",
+		Status:          "Delivered",
+		Timestamp:       "2025-01-02T09:00:05+03:00",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.DELIVERED, message.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRecordDeliveryReceipt_FailedKeepsReason(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("GetMessageByRemoteID", ctx, "default", "remote-42").Return(createSentMessage(), nil)
+	mockRepo.On("RecordDelivery", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.UNDELIVERED && msg.DeliveryError == "recipient unreachable"
+	})).Return(true, nil).Once()
+
+	message, err := service.RecordDeliveryReceipt(ctx, DeliveryReceipt{
+		Provider:        "default",
+		RemoteMessageID: "remote-42",
+		Status:          ReceiptFailed,
+		Timestamp:       "2025-01-02T06:00:05Z",
+		Reason:          "recipient unreachable",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.UNDELIVERED, message.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRecordDeliveryReceipt_IgnoresSupersededReceipts(t *testing.T) {
+	tests := []struct {
+		name    string
+		current status.MessageStatus
+		receipt DeliveryReceipt
+	}{
+		{
+			name:    "older than recorded",
+			current: status.DELIVERED,
+			receipt: DeliveryReceipt{Provider: "default", RemoteMessageID: "remote-42", Status: ReceiptFailed, Timestamp: "2025-01-02T06:00:00Z"},
+		},
+		{
+			name:    "duplicate",
+			current: status.DELIVERED,
+			receipt: DeliveryReceipt{Provider: "default", RemoteMessageID: "remote-42", Status: ReceiptDelivered, Timestamp: "2025-01-02T06:00:05Z"},
+		},
+		{
+			name:    "read after delivered",
+			current: status.DELIVERED,
+			receipt: DeliveryReceipt{Provider: "default", RemoteMessageID: "remote-42", Status: ReceiptRead, Timestamp: "2025-01-02T07:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MessagesRepositoryMock{}
+			service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+			ctx := context.Background()
+			message := createSentMessage()
+			message.Status = tt.current
+			message.DeliveryReportedAt = "2025-01-02T06:00:05Z"
+			mockRepo.On("GetMessageByRemoteID", ctx, "default", "remote-42").Return(message, nil)
+
+			result, err := service.RecordDeliveryReceipt(ctx, tt.receipt)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.current, result.Status)
+			mockRepo.AssertNotCalled(t, "RecordDelivery", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRecordDeliveryReceipt_Errors(t *testing.T) {
+	ctx := context.Background()
+	valid := DeliveryReceipt{Provider: "default", RemoteMessageID: "remote-42", Status: ReceiptDelivered}
+
+	t.Run("invalid receipt", func(t *testing.T) {
+		mockRepo := &mocks.MessagesRepositoryMock{}
+		service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+		for _, receipt := range []DeliveryReceipt{
+			{Status: ReceiptDelivered},
+			{RemoteMessageID: "remote-42", Status: "bounced"},
+			{RemoteMessageID: "remote-42", Status: ReceiptDelivered, Timestamp: "yesterday"},
+		} {
+			_, err := service.RecordDeliveryReceipt(ctx, receipt)
+			assert.ErrorAs(t, err, &port.ValidationError{})
+		}
+		mockRepo.AssertNotCalled(t, "GetMessageByRemoteID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown remote id", func(t *testing.T) {
+		mockRepo := &mocks.MessagesRepositoryMock{}
+		service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+		mockRepo.On("GetMessageByRemoteID", ctx, "default", "remote-42").Return(nil, repository.ErrMessageNotFound)
+
+		_, err := service.RecordDeliveryReceipt(ctx, valid)
+
+		assert.ErrorAs(t, err, &port.NotFoundError{})
+	})
+
+	t.Run("message not sent", func(t *testing.T) {
+		mockRepo := &mocks.MessagesRepositoryMock{}
+		service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+		message := createSentMessage()
+		message.Status = status.SENDING
+		mockRepo.On("GetMessageByRemoteID", ctx, "default", "remote-42").Return(message, nil)
+
+		_, err := service.RecordDeliveryReceipt(ctx, valid)
+
+		assert.ErrorAs(t, err, &port.ConflictError{})
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		mockRepo := &mocks.MessagesRepositoryMock{}
+		service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+		mockRepo.On("GetMessageByRemoteID", ctx, "default", "remote-42").Return(createSentMessage(), nil)
+		mockRepo.On("RecordDelivery", ctx, mock.Anything).Return(false, nil)
+
+		_, err := service.RecordDeliveryReceipt(ctx, valid)
+
+		assert.ErrorAs(t, err, &port.ConflictError{})
+	})
+}
+
+func TestVerifyDeliveryCallback(t *testing.T) {
+	verifier, err := signature.NewVerifier(0, "provider-secret")
+	assert.NoError(t, err)
+	signer, err := signature.NewSigner("provider-secret")
+	assert.NoError(t, err)
+	forger, err := signature.NewSigner("guessed-secret")
+	assert.NoError(t, err)
+
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, &mocks.SchedulerMock{},
+		WithCallbackVerifiers(map[string]*signature.Verifier{"default": verifier}))
+	body := []byte(`{"messageId":"remote-42","status":"delivered"}`)
+
+	assert.NoError(t, service.VerifyDeliveryCallback("default", signer.Sign(body), body))
+	assert.ErrorAs(t, service.VerifyDeliveryCallback("default", "", body), &port.UnauthorizedError{})
+	assert.ErrorAs(t, service.VerifyDeliveryCallback("default", forger.Sign(body), body), &port.UnauthorizedError{})
+	assert.ErrorAs(t, service.VerifyDeliveryCallback("default", signer.Sign(body), []byte(`{"messageId":"remote-43","status":"delivered"}`)), &port.UnauthorizedError{})
+	// a provider without callback secrets can not report receipts at all
+	assert.ErrorAs(t, service.VerifyDeliveryCallback("international", signer.Sign(body), body), &port.UnauthorizedError{})
+}
@@ -0,0 +1,169 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"time"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 100
+)
+
+// MessageQuery filters SearchMessages. CreatedFrom and CreatedTo are RFC 3339 timestamps bounding
+// created_at, from inclusive and to exclusive. Cursor is the NextCursor of a previous page.
+type MessageQuery struct {
+	Statuses        []status.MessageStatus
+	Phone           string
+	RemoteMessageID string
+	Tags            []string
+	CreatedFrom     string
+	CreatedTo       string
+	Cursor          string
+	Limit           int
+}
+
+// MessagePage holds one page of search results, newest first. NextCursor is empty on the last page.
+type MessagePage struct {
+	Messages   []*entity.MessagesEntity
+	NextCursor string
+}
+
+// MessageDetails is a message together with every recorded change of it, oldest first.
+type MessageDetails struct {
+	Message *entity.MessagesEntity
+	History []*entity.MessageHistoryEntity
+}
+
+// messageCursor is serialized into the opaque cursor handed to clients.
+type messageCursor struct {
+	CreatedAt string `json:"c"`
+	ID        string `json:"i"`
+}
+
+func (is *MessageSendService) SearchMessages(ctx context.Context, query MessageQuery) (MessagePage, error) {
+	filter, err := toMessageFilter(query)
+	if err != nil {
+		return MessagePage{}, err
+	}
+
+	// one extra row tells whether another page follows
+	limit := filter.Limit
+	filter.Limit++
+
+	messages, err := is.repo.SearchMessages(ctx, filter)
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to search messages")
+		return MessagePage{}, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	page := MessagePage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		last := page.Messages[limit-1]
+		page.NextCursor = encodeCursor(messageCursor{CreatedAt: last.CreatedAt, ID: last.Id})
+	}
+
+	return page, nil
+}
+
+func (is *MessageSendService) GetMessageDetails(ctx context.Context, id string) (*MessageDetails, error) {
+	message, err := is.repo.GetMessage(ctx, id)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return nil, port.NotFoundError{Msg: fmt.Sprintf("message %s not found", id)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	history, err := is.repo.GetMessageHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message history: %w", err)
+	}
+
+	return &MessageDetails{Message: message, History: history}, nil
+}
+
+func toMessageFilter(query MessageQuery) (repository.MessageFilter, error) {
+	filter := repository.MessageFilter{
+		Statuses:        query.Statuses,
+		Phone:           query.Phone,
+		RemoteMessageID: query.RemoteMessageID,
+		Tags:            query.Tags,
+		Limit:           query.Limit,
+	}
+
+	for _, messageStatus := range query.Statuses {
+		if !messageStatus.Valid() {
+			return filter, port.ValidationError{Msg: fmt.Sprintf("unknown status %q", messageStatus)}
+		}
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultSearchLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxSearchLimit {
+		return filter, port.ValidationError{Msg: fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)}
+	}
+
+	var err error
+	if filter.CreatedFrom, err = utcTimestamp("from", query.CreatedFrom); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = utcTimestamp("to", query.CreatedTo); err != nil {
+		return filter, err
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return filter, port.ValidationError{Msg: "invalid cursor", WrappedErr: err}
+		}
+		filter.After = &repository.MessagePosition{CreatedAt: cursor.CreatedAt, ID: cursor.ID}
+	}
+
+	return filter, nil
+}
+
+func utcTimestamp(name string, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", port.ValidationError{Msg: fmt.Sprintf("%s must be an RFC 3339 timestamp, e.g. 2025-01-02T09:00:00+03:00", name)}
+	}
+	return parsed.UTC().Format(time.RFC3339Nano), nil
+}
+
+func encodeCursor(cursor messageCursor) string {
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeCursor(value string) (messageCursor, error) {
+	var cursor messageCursor
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.CreatedAt == "" || cursor.ID == "" {
+		return cursor, errors.New("cursor is missing its position")
+	}
+
+	return cursor, nil
+}
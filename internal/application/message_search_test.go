@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchMessages_PagesWithCursor(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	newest := &entity.MessagesEntity{Id: "3", CreatedAt: "2025-01-01T10:02:00Z"}
+	middle := &entity.MessagesEntity{Id: "2", CreatedAt: "2025-01-01T10:01:00Z"}
+	oldest := &entity.MessagesEntity{Id: "1", CreatedAt: "2025-01-01T10:00:00Z"}
+
+	mockRepo.On("SearchMessages", ctx, mock.MatchedBy(func(filter repository.MessageFilter) bool {
+		return filter.After == nil && filter.Limit == 3
+	})).Return([]*entity.MessagesEntity{newest, middle, oldest}, nil).Once()
+
+	page, err := service.SearchMessages(ctx, MessageQuery{
+		Statuses:    []status.MessageStatus{status.FAILED},
+		CreatedFrom: "2025-01-01T12:00:00+03:00",
+		Limit:       2,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.MessagesEntity{newest, middle}, page.Messages)
+	assert.NotEmpty(t, page.NextCursor)
+
+	mockRepo.On("SearchMessages", ctx, mock.MatchedBy(func(filter repository.MessageFilter) bool {
+		return filter.After != nil && *filter.After == repository.MessagePosition{CreatedAt: middle.CreatedAt, ID: middle.Id}
+	})).Return([]*entity.MessagesEntity{oldest}, nil).Once()
+
+	page, err = service.SearchMessages(ctx, MessageQuery{Limit: 2, Cursor: page.NextCursor})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.MessagesEntity{oldest}, page.Messages)
+	assert.Empty(t, page.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchMessages_NormalizesFilter(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("SearchMessages", ctx, repository.MessageFilter{
+		Phone:       "+905551234567",
+		Tags:        []string{"campaign-42"},
+		CreatedFrom: "2025-01-01T09:00:00Z",
+		CreatedTo:   "2025-01-02T09:00:00Z",
+		Limit:       defaultSearchLimit + 1,
+	}).Return([]*entity.MessagesEntity{}, nil).Once()
+
+	_, err := service.SearchMessages(ctx, MessageQuery{
+		Phone:       "+905551234567",
+		Tags:        []string{"campaign-42"},
+		CreatedFrom: "2025-01-01T12:00:00+03:00",
+		CreatedTo:   "2025-01-02T12:00:00+03:00",
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchMessages_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query MessageQuery
+	}{
+		{name: "unknown status", query: MessageQuery{Statuses: []status.MessageStatus{"lost"}}},
+		{name: "limit too large", query: MessageQuery{Limit: maxSearchLimit + 1}},
+		{name: "malformed from", query: MessageQuery{CreatedFrom: "yesterday"}},
+		{name: "malformed to", query: MessageQuery{CreatedTo: "2025-01-01"}},
+		{name: "malformed cursor", query: MessageQuery{Cursor: "not a cursor"}},
+		{name: "empty cursor position", query: MessageQuery{Cursor: encodeCursor(messageCursor{})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MessagesRepositoryMock{}
+			service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+			_, err := service.SearchMessages(context.Background(), tt.query)
+
+			assert.ErrorAs(t, err, &port.ValidationError{})
+			mockRepo.AssertNotCalled(t, "SearchMessages", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetMessageDetails_Success(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	message := createPendingMessage()
+	history := []*entity.MessageHistoryEntity{
+		{Id: "1", MessageId: "42", Status: status.UNSENT, Version: 1},
+		{Id: "2", MessageId: "42", Status: status.UNSENT, Version: 3},
+	}
+	mockRepo.On("GetMessage", ctx, "42").Return(message, nil)
+	mockRepo.On("GetMessageHistory", ctx, "42").Return(history, nil)
+
+	details, err := service.GetMessageDetails(ctx, "42")
+
+	assert.NoError(t, err)
+	assert.Equal(t, message, details.Message)
+	assert.Equal(t, history, details.History)
+}
+
+func TestGetMessageDetails_NotFound(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	ctx := context.Background()
+	mockRepo.On("GetMessage", ctx, "42").Return(nil, repository.ErrMessageNotFound)
+
+	_, err := service.GetMessageDetails(ctx, "42")
+
+	assert.ErrorAs(t, err, &port.NotFoundError{})
+	mockRepo.AssertNotCalled(t, "GetMessageHistory", mock.Anything, mock.Anything)
+}
@@ -2,30 +2,95 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/attempt"
+	"message-scheduler/internal/domain/types/priority"
 	"message-scheduler/internal/domain/types/status"
 	"message-scheduler/internal/infra/client/webhook"
 	"message-scheduler/internal/infra/repository"
 	"message-scheduler/internal/port"
 	"message-scheduler/log"
+	"message-scheduler/signature"
+	"os"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultLeaseDuration must comfortably exceed the job timeout, otherwise a message still being
+	// sent could be released by the reaper and claimed by another instance.
+	defaultLeaseDuration     = 5 * time.Minute
+	defaultLeaseReapInterval = time.Minute
 )
 
 type MessageSendService struct {
 	client           webhook.WebhookClient
 	repo             repository.MessagesRepository
+	sendAttempts     repository.SendAttemptsRepository
+	templates        repository.TemplatesRepository
+	defaultLocale    string
+	outbox           *outboxRelay
 	scheduler        port.Scheduler
 	schedulerRunning bool
+	retryPolicy      RetryPolicy
+	jobsScheduled    bool
+	leaseOwner       string
+	leaseDuration    time.Duration
+	leaseReapEvery   time.Duration
+	settings         SchedulerSettings
+	settingsMutex    sync.RWMutex
+	settingsStore    repository.SchedulerSettingsRepository
+	callbacks        map[string]*signature.Verifier
+}
+
+type Option func(*MessageSendService)
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(is *MessageSendService) {
+		is.retryPolicy = policy
+	}
+}
+
+// WithLease configures how long claimed messages stay reserved for this instance and how often
+// expired leases of crashed instances are returned to the queue.
+func WithLease(owner string, duration time.Duration, reapInterval time.Duration) Option {
+	return func(is *MessageSendService) {
+		if owner != "" {
+			is.leaseOwner = owner
+		}
+		is.leaseDuration = duration
+		is.leaseReapEvery = reapInterval
+	}
 }
 
-func NewMessageSendService(webhookClient webhook.WebhookClient, messagesRepo repository.MessagesRepository, scheduler port.Scheduler) *MessageSendService {
-	return &MessageSendService{
+func NewMessageSendService(webhookClient webhook.WebhookClient, messagesRepo repository.MessagesRepository, scheduler port.Scheduler, opts ...Option) *MessageSendService {
+	service := &MessageSendService{
 		client:           webhookClient,
 		repo:             messagesRepo,
 		scheduler:        scheduler,
 		schedulerRunning: false,
+		retryPolicy:      DefaultRetryPolicy(),
+		leaseOwner:       defaultLeaseOwner(),
+		leaseDuration:    defaultLeaseDuration,
+		leaseReapEvery:   defaultLeaseReapInterval,
+		settings:         DefaultSchedulerSettings(),
 	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	if err := service.validateSchedulerSettings(service.settings); err != nil {
+		log.Logger.Error().Err(err).Msg("Invalid scheduler settings, using the defaults")
+		service.settings = DefaultSchedulerSettings()
+	}
+
+	return service
 }
 
 func (is *MessageSendService) StartScheduler(ctx context.Context) {
@@ -33,13 +98,13 @@ func (is *MessageSendService) StartScheduler(ctx context.Context) {
 	if !is.schedulerRunning && is.scheduler != nil {
 		log.Logger.Info().Msg("Starting continuous message processing scheduler...")
 
-		continuousJob := &continuousMessageProcessorJob{
-			messageService: is,
-			limit:          2,
+		// jobs stay registered with the scheduler across stop/start cycles
+		if !is.jobsScheduled {
+			is.scheduler.ScheduleJob(&continuousMessageProcessorJob{messageService: is}, is.SchedulerSettings().Interval)
+			is.scheduler.ScheduleJob(&leaseReaperJob{messageService: is}, is.leaseReapEvery)
+			is.jobsScheduled = true
 		}
 
-		is.scheduler.ScheduleJob(continuousJob, 2*time.Minute)
-
 		go func() {
 			is.scheduler.Start(ctx)
 		}()
@@ -69,19 +134,216 @@ func (is *MessageSendService) StopScheduler() error {
 	return err
 }
 
+type SchedulerStatus struct {
+	Running bool
+	// LeaderElection is set when only the elected instance runs the jobs, Leader tells if it is this one.
+	LeaderElection bool
+	Leader         bool
+}
+
+// CircuitBreakerStatus reports the state of the webhook circuit breaker, ok is false when the client has none.
+func (is *MessageSendService) CircuitBreakerStatus() (webhook.BreakerStatus, bool) {
+	breakerAware, ok := is.client.(webhook.BreakerAware)
+	if !ok {
+		return webhook.BreakerStatus{}, false
+	}
+
+	return breakerAware.BreakerStatus(), true
+}
+
+// ProviderBreakerStatuses reports the circuit breakers of each provider when messages are routed
+// over several providers, ok is false otherwise.
+func (is *MessageSendService) ProviderBreakerStatuses() (map[string]webhook.BreakerStatus, bool) {
+	multiBreakerAware, ok := is.client.(webhook.MultiBreakerAware)
+	if !ok {
+		return nil, false
+	}
+
+	return multiBreakerAware.BreakerStatuses(), true
+}
+
+func (is *MessageSendService) SchedulerStatus() SchedulerStatus {
+	schedulerStatus := SchedulerStatus{Running: is.schedulerRunning}
+
+	if leaderAware, ok := is.scheduler.(port.LeaderAware); ok {
+		schedulerStatus.LeaderElection = true
+		schedulerStatus.Leader = leaderAware.IsLeader()
+	}
+
+	return schedulerStatus
+}
+
 type continuousMessageProcessorJob struct {
 	messageService *MessageSendService
-	limit          int
 }
 
 func (j *continuousMessageProcessorJob) Execute(ctx context.Context) error {
-	return j.messageService.ProcessUnsentMessages(ctx, j.limit)
+	// only the elected instance runs this job, settings changed on another instance reach it here
+	settings, err := j.messageService.RefreshSchedulerSettings(ctx)
+	if err != nil {
+		log.Logger.Warn().Err(err).Msg("Failed to refresh scheduler settings, keeping the current ones")
+	}
+
+	_, err = j.messageService.ProcessUnsentMessages(ctx, settings.BatchSize)
+	return err
 }
 
 func (j *continuousMessageProcessorJob) Name() string {
 	return "ContinuousMessageProcessor"
 }
 
+func (j *continuousMessageProcessorJob) Interval() time.Duration {
+	return j.messageService.SchedulerSettings().Interval
+}
+
+func (j *continuousMessageProcessorJob) Timeout() time.Duration {
+	return j.messageService.SchedulerSettings().JobTimeout
+}
+
+type leaseReaperJob struct {
+	messageService *MessageSendService
+}
+
+func (j *leaseReaperJob) Execute(ctx context.Context) error {
+	_, err := j.messageService.ReleaseExpiredLeases(ctx)
+	return err
+}
+
+func (j *leaseReaperJob) Name() string {
+	return "LeaseReaper"
+}
+
+func defaultLeaseOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "message-scheduler"
+	}
+	return hostname + "-" + uuid.NewString()[:8]
+}
+
+func (is *MessageSendService) CreateMessage(ctx context.Context, message *entity.MessagesEntity) error {
+	err := is.applyTemplate(ctx, message, make(map[string]*entity.TemplateEntity))
+	if err == nil {
+		err = prepareNewMessage(message)
+	}
+	if err != nil {
+		if !errors.As(err, &port.ValidationError{}) {
+			return err
+		}
+		log.Logger.Warn().Err(err).Str("phone", message.Phone).Msg("Rejected invalid message")
+		return err
+	}
+
+	if err := is.repo.Create(ctx, message); err != nil {
+		log.Logger.Error().Err(err).Str("phone", message.Phone).Msg("Failed to create message")
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	log.Logger.Info().Str("message_id", message.Id).Str("phone", message.Phone).Msg("Message created")
+	return nil
+}
+
+type BatchItemResult struct {
+	Index   int
+	Message *entity.MessagesEntity
+	Err     error
+}
+
+// CreateMessages validates every message independently and stores the valid ones in a single
+// repository call. Invalid messages are reported in their result without failing the batch.
+func (is *MessageSendService) CreateMessages(ctx context.Context, messages []*entity.MessagesEntity) ([]BatchItemResult, error) {
+	results := make([]BatchItemResult, len(messages))
+	accepted := make([]*entity.MessagesEntity, 0, len(messages))
+	templates := make(map[string]*entity.TemplateEntity)
+
+	for i, message := range messages {
+		results[i] = BatchItemResult{Index: i, Message: message}
+
+		if message == nil {
+			results[i].Err = port.ValidationError{Msg: "message must not be empty"}
+			continue
+		}
+
+		err := is.applyTemplate(ctx, message, templates)
+		if err == nil {
+			err = prepareNewMessage(message)
+		}
+		if err != nil {
+			if !errors.As(err, &port.ValidationError{}) {
+				return nil, err
+			}
+			results[i].Err = err
+			continue
+		}
+
+		accepted = append(accepted, message)
+	}
+
+	if len(accepted) > 0 {
+		if err := is.repo.SaveBatch(ctx, accepted); err != nil {
+			log.Logger.Error().Err(err).Int("count", len(accepted)).Msg("Failed to create message batch")
+			return nil, fmt.Errorf("failed to create messages: %w", err)
+		}
+	}
+
+	log.Logger.Info().
+		Int("received", len(messages)).
+		Int("accepted", len(accepted)).
+		Int("rejected", len(messages)-len(accepted)).
+		Msg("Message batch created")
+
+	return results, nil
+}
+
+func prepareNewMessage(message *entity.MessagesEntity) error {
+	message.Phone = strings.TrimSpace(message.Phone)
+	message.Priority = normalizePriority(message.Priority)
+	message.Tags = normalizeTags(message.Tags)
+	message.Locale = canonicalLocale(message.Locale)
+
+	if err := validateMessage(message); err != nil {
+		return err
+	}
+
+	message.Status = status.UNSENT
+	message.SendAt = normalizeSendAt(message.SendAt, time.Now())
+	return nil
+}
+
+// normalizeTags trims tags and drops duplicates, keeping the first occurrence.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// normalizePriority accepts any letter case and falls back to NORMAL when no priority is given.
+func normalizePriority(messagePriority priority.MessagePriority) priority.MessagePriority {
+	normalized := strings.ToLower(strings.TrimSpace(string(messagePriority)))
+	if normalized == "" {
+		return priority.NORMAL
+	}
+	return priority.MessagePriority(normalized)
+}
+
+// normalizeSendAt stores the send time in UTC like every other timestamp; messages without one are due right away.
+func normalizeSendAt(sendAt string, now time.Time) string {
+	if sendAt == "" {
+		return now.UTC().Format(time.RFC3339)
+	}
+
+	parsed, _ := time.Parse(time.RFC3339, sendAt)
+	return parsed.UTC().Format(time.RFC3339)
+}
+
 func (is *MessageSendService) GetUnsentMessages(ctx context.Context, limit int) ([]*entity.MessagesEntity, error) {
 	log.Logger.Info().Int("limit", limit).Msg("Getting unsent messages...")
 
@@ -108,46 +370,215 @@ func (is *MessageSendService) GetSentMessages(ctx context.Context, limit int) ([
 	return sentMessages, nil
 }
 
-func (is *MessageSendService) ProcessUnsentMessages(ctx context.Context, limit int) error {
-	unsentMessages, err := is.GetUnsentMessages(ctx, limit)
+func (is *MessageSendService) ClaimUnsentMessages(ctx context.Context, limit int) ([]*entity.MessagesEntity, error) {
+	settings := is.SchedulerSettings()
+	claimedMessages, err := is.repo.ClaimMessages(ctx, repository.ClaimOptions{
+		Owner:                  is.leaseOwner,
+		Lease:                  is.leaseDuration,
+		PriorityAging:          settings.PriorityAging,
+		Limit:                  limit,
+		PreserveRecipientOrder: settings.PreserveRecipientOrder,
+		Routes:                 is.claimRoutes(ctx),
+	})
 	if err != nil {
-		return err
+		log.Logger.Error().Err(err).Str("lease_owner", is.leaseOwner).Msg("Failed to claim unsent messages")
+		return nil, fmt.Errorf("failed to claim unsent messages: %w", err)
+	}
+
+	log.Logger.Info().Int("count", len(claimedMessages)).Str("lease_owner", is.leaseOwner).Msg("Claimed unsent messages")
+	return claimedMessages, nil
+}
+
+// claimRoutes hands the routing of the webhook client to the claim, so messages of a provider that
+// is out of quota or has its breaker open stay queued while the other providers get a full batch.
+func (is *MessageSendService) claimRoutes(ctx context.Context) *repository.ClaimRoutes {
+	router, ok := is.client.(webhook.RouteAware)
+	if !ok {
+		return nil
+	}
+
+	routing := router.Routing(ctx)
+	rules := make([]repository.RouteRule, len(routing.Rules))
+	for i, rule := range routing.Rules {
+		var priorities []string
+		for _, p := range rule.Priorities {
+			priorities = append(priorities, string(p))
+		}
+		rules[i] = repository.RouteRule{
+			Provider:      rule.Provider,
+			PhonePrefixes: rule.PhonePrefixes,
+			Tags:          rule.Tags,
+			Priorities:    priorities,
+		}
+	}
+
+	return &repository.ClaimRoutes{Rules: rules, Fallback: routing.Fallback, Quotas: routing.Available}
+}
+
+func (is *MessageSendService) ReleaseExpiredLeases(ctx context.Context) (int64, error) {
+	released, err := is.repo.ReleaseExpiredLeases(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release expired leases: %w", err)
+	}
+	return released, nil
+}
+
+// ProcessUnsentMessages claims up to limit due messages and dispatches them through the worker pool.
+func (is *MessageSendService) ProcessUnsentMessages(ctx context.Context, limit int) (ProcessResult, error) {
+	if quota, ok := is.client.(webhook.QuotaAware); ok {
+		available := quota.Available(ctx)
+		if available <= 0 {
+			log.Logger.Info().Msg("Webhook quota exhausted, not claiming messages this tick")
+			return ProcessResult{}, nil
+		}
+		limit = min(limit, available)
+	}
+
+	unsentMessages, err := is.ClaimUnsentMessages(ctx, limit)
+	if err != nil {
+		return ProcessResult{}, err
 	}
 
 	log.Logger.Info().Int("unsent_count", len(unsentMessages)).Msg("Starting to process unsent messages")
 
-	for _, message := range unsentMessages {
+	result := is.dispatch(ctx, unsentMessages)
+
+	log.Logger.Info().
+		Int("succeeded", result.Succeeded).
+		Int("failed", result.Failed).
+		Int("skipped", result.Skipped).
+		Msg("Finished processing unsent messages")
+
+	return result, nil
+}
+
+func (is *MessageSendService) sendMessage(ctx context.Context, message *entity.MessagesEntity) dispatchOutcome {
+	log.Logger.Info().
+		Str("message_id", message.Id).
+		Str("phone", message.Phone).
+		Msg("Processing unsent message")
+
+	message.Attempts++
+	message.LeaseOwner = ""
+	message.LeaseExpiresAt = ""
+
+	sendAttempt, err := is.beginSendAttempt(ctx, message)
+	if err != nil {
+		// without a record of the attempt a crash could not be reconciled, so do not send at all
+		message.Attempts--
+		is.releaseMessage(ctx, message)
+		return outcomeSkipped
+	}
+	if sendAttempt.Status == attempt.SUCCEEDED {
+		log.Logger.Warn().
+			Str("message_id", message.Id).
+			Str("idempotency_key", sendAttempt.IdempotencyKey).
+			Msg("Message was already sent by an earlier attempt, reconciling without sending again")
+
+		is.markSent(ctx, message, &webhook.WebhookResponse{MessageID: sendAttempt.RemoteMessageId, Provider: sendAttempt.Provider})
+		return outcomeSucceeded
+	}
+
+	if err := is.renderContent(ctx, message); err != nil {
+		log.Logger.Error().
+			Err(err).
+			Str("message_id", message.Id).
+			Str("template_id", message.TemplateId).
+			Msg("Failed to render message template")
+
+		is.finishSendAttempt(ctx, sendAttempt, attempt.FAILED, nil, err)
+		is.recordFailure(ctx, message, err)
+		return outcomeFailed
+	}
+
+	response, err := is.client.SendMessage(ctx, message, sendAttempt.IdempotencyKey)
+	if errors.Is(err, webhook.ErrRateLimited) || errors.Is(err, webhook.ErrCircuitOpen) {
+		// nothing was sent, so the attempt does not count; the next try reuses the pending attempt
+		message.Attempts--
+		is.releaseMessage(ctx, message)
+		return outcomeSkipped
+	}
+	if err != nil {
+		log.Logger.Error().
+			Err(err).
+			Str("message_id", message.Id).
+			Str("phone", message.Phone).
+			Int("attempts", message.Attempts).
+			Msg("Failed to send unsent message")
+
+		is.finishSendAttempt(ctx, sendAttempt, attempt.FAILED, nil, err)
+		is.recordFailure(ctx, message, err)
+		return outcomeFailed
+	}
+
+	// the outcome is stored before the message, so a failed save can be reconciled from it
+	is.finishSendAttempt(ctx, sendAttempt, attempt.SUCCEEDED, response, nil)
+	is.markSent(ctx, message, response)
+
+	return outcomeSucceeded
+}
+
+func (is *MessageSendService) markSent(ctx context.Context, message *entity.MessagesEntity, response *webhook.WebhookResponse) {
+	message.Status = status.SENT
+	message.RemoteMessageId = response.MessageID
+	message.Provider = response.Provider
+	message.SentAt = time.Now().UTC().Format(time.RFC3339)
+	message.NextAttemptAt = ""
+
+	if saved, saveErr := is.saveClaimed(ctx, message); saveErr != nil {
+		log.Logger.Error().Err(saveErr).Str("message_id", message.Id).Msg("Failed to update message status to SENT")
+	} else if saved {
 		log.Logger.Info().
 			Str("message_id", message.Id).
 			Str("phone", message.Phone).
-			Msg("Processing unsent message")
+			Str("remote_message_id", message.RemoteMessageId).
+			Str("provider", message.Provider).
+			Str("sent_at", message.SentAt).
+			Msg("Unsent message sent successfully and status updated")
+	}
+}
 
-		response, err := is.client.SendMessage(ctx, message.Phone, message.Content)
-		if err != nil {
-			log.Logger.Error().
-				Err(err).
-				Str("message_id", message.Id).
-				Str("phone", message.Phone).
-				Msg("Failed to send unsent message")
+func (is *MessageSendService) recordFailure(ctx context.Context, message *entity.MessagesEntity, sendErr error) {
+	is.retryPolicy.applyFailure(message, sendErr, time.Now())
 
-			continue
-		}
+	saved, saveErr := is.saveClaimed(ctx, message)
+	if saveErr != nil {
+		log.Logger.Error().Err(saveErr).Str("message_id", message.Id).Str("status", string(message.Status)).Msg("Failed to record failed delivery attempt")
+		return
+	}
+	if !saved {
+		return
+	}
 
-		message.Status = status.SENT
-		message.RemoteMessageId = response.MessageID
-		message.SentAt = time.Now().Format(time.RFC3339)
-
-		if saveErr := is.repo.Save(ctx, message); saveErr != nil {
-			log.Logger.Error().Err(saveErr).Str("message_id", message.Id).Msg("Failed to update message status to SENT")
-		} else {
-			log.Logger.Info().
-				Str("message_id", message.Id).
-				Str("phone", message.Phone).
-				Str("remote_message_id", message.RemoteMessageId).
-				Str("sent_at", message.SentAt).
-				Msg("Unsent message sent successfully and status updated")
-		}
+	event := log.Logger.Warn()
+	if message.Status == status.DEAD || message.Status == status.FAILED {
+		event = log.Logger.Error()
 	}
 
-	return nil
+	event.
+		Str("message_id", message.Id).
+		Str("status", string(message.Status)).
+		Int("attempts", message.Attempts).
+		Str("next_attempt_at", message.NextAttemptAt).
+		Msg("Recorded failed delivery attempt")
+}
+
+// saveClaimed writes the outcome of a claimed message unless this instance lost its lease meanwhile.
+// A lost lease means the reaper released the message and another instance may already own it, so
+// the outcome is dropped rather than overwriting that instance's progress; the recorded send
+// attempt lets the new owner reconcile a message this instance did send.
+func (is *MessageSendService) saveClaimed(ctx context.Context, message *entity.MessagesEntity) (bool, error) {
+	saved, err := is.repo.SaveClaimed(ctx, message, is.leaseOwner)
+	if err != nil {
+		return false, err
+	}
+
+	if !saved {
+		log.Logger.Warn().
+			Str("message_id", message.Id).
+			Str("status", string(message.Status)).
+			Str("lease_owner", is.leaseOwner).
+			Msg("Lost the lease on the message before saving it, leaving it to its current owner")
+	}
+	return saved, nil
 }
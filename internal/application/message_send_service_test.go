@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
 	"message-scheduler/internal/domain/types/status"
 	"message-scheduler/internal/infra/client/webhook"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
 	"message-scheduler/mocks"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +19,13 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+// claimLimit matches a claim of up to limit messages, whatever its other options.
+func claimLimit(limit int) interface{} {
+	return mock.MatchedBy(func(claim repository.ClaimOptions) bool {
+		return claim.Limit == limit
+	})
+}
+
 func createTestMessage(messageStatus status.MessageStatus) *entity.MessagesEntity {
 	return &entity.MessagesEntity{
 		Id:              uuid.New().String(),
@@ -36,6 +47,7 @@ func TestSendMessage_Success(t *testing.T) {
 	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
 
 	mockScheduler.On("ScheduleJob", mock.Anything, 2*time.Minute).Return()
+	mockScheduler.On("ScheduleJob", mock.Anything, defaultLeaseReapInterval).Return()
 	mockScheduler.On("Start", mock.Anything).Return()
 
 	ctx := context.Background()
@@ -67,15 +79,16 @@ func TestProcessUnsentMessages_Success(t *testing.T) {
 		MessageID: "webhook-msg-123",
 	}
 
-	mockRepo.On("GetUnsentMessages", ctx, limit).Return(unsentMessages, nil)
-	mockWebhook.On("SendMessage", ctx, unsentMessages[0].Phone, unsentMessages[0].Content).Return(webhookResponse, nil)
-	mockRepo.On("Save", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+	mockRepo.On("ClaimMessages", ctx, claimLimit(limit)).Return(unsentMessages, nil)
+	mockWebhook.On("SendMessage", ctx, mock.Anything, mock.Anything).Return(webhookResponse, nil).Twice()
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
 		return msg.Status == status.SENT && msg.RemoteMessageId == "webhook-msg-123"
-	})).Return(nil).Twice()
+	}), mock.Anything).Return(true, nil).Twice()
 
-	err := service.ProcessUnsentMessages(ctx, limit)
+	result, err := service.ProcessUnsentMessages(ctx, limit)
 
 	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Succeeded: 2}, result)
 	mockRepo.AssertExpectations(t)
 	mockWebhook.AssertExpectations(t)
 }
@@ -99,11 +112,11 @@ func TestProcessUnsentMessages_SaveFailure(t *testing.T) {
 		MessageID: "webhook-msg-123",
 	}
 
-	mockRepo.On("GetUnsentMessages", ctx, limit).Return(unsentMessages, nil)
-	mockWebhook.On("SendMessage", ctx, unsentMessages[0].Phone, unsentMessages[0].Content).Return(webhookResponse, nil)
-	mockRepo.On("Save", ctx, mock.Anything).Return(fmt.Errorf("database error"))
+	mockRepo.On("ClaimMessages", ctx, claimLimit(limit)).Return(unsentMessages, nil)
+	mockWebhook.On("SendMessage", ctx, unsentMessages[0], mock.Anything).Return(webhookResponse, nil)
+	mockRepo.On("SaveClaimed", ctx, mock.Anything, mock.Anything).Return(false, fmt.Errorf("database error"))
 
-	err := service.ProcessUnsentMessages(ctx, limit)
+	_, err := service.ProcessUnsentMessages(ctx, limit)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -272,21 +285,28 @@ func TestContinuousMessageProcessorJob_Execute(t *testing.T) {
 	mockRepo := &mocks.MessagesRepositoryMock{}
 	mockScheduler := &mocks.SchedulerMock{}
 
-	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler, WithSchedulerSettings(SchedulerSettings{
+		BatchSize:     3,
+		Interval:      time.Minute,
+		JobTimeout:    10 * time.Second,
+		Concurrency:   2,
+		PriorityAging: time.Minute,
+	}))
 
 	job := &continuousMessageProcessorJob{
 		messageService: service,
-		limit:          3,
 	}
 
 	ctx := context.Background()
 
-	mockRepo.On("GetUnsentMessages", ctx, 3).Return([]*entity.MessagesEntity{}, nil)
+	mockRepo.On("ClaimMessages", ctx, claimLimit(3)).Return([]*entity.MessagesEntity{}, nil)
 
 	err := job.Execute(ctx)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "ContinuousMessageProcessor", job.Name())
+	assert.Equal(t, time.Minute, job.Interval())
+	assert.Equal(t, 10*time.Second, job.Timeout())
 	mockRepo.AssertExpectations(t)
 }
 
@@ -304,3 +324,592 @@ func TestSendMessage_SchedulerAlreadyRunning(t *testing.T) {
 
 	assert.True(t, service.schedulerRunning)
 }
+
+func TestCreateMessage_Success(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+	message := &entity.MessagesEntity{
+		Phone:   " +905551234567 ",
+		Content: "Test message content",
+	}
+
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.UNSENT && msg.Phone == "+905551234567"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.MessagesEntity).Id = "42"
+	}).Return(nil)
+
+	err := service.CreateMessage(ctx, message)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "42", message.Id)
+	assert.Equal(t, status.UNSENT, message.Status)
+	assert.Equal(t, priority.NORMAL, message.Priority)
+	sendAt, parseErr := time.Parse(time.RFC3339, message.SendAt)
+	assert.NoError(t, parseErr)
+	assert.WithinDuration(t, time.Now(), sendAt, 5*time.Second)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMessage_NormalizesSendAtAndPriority(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+	message := &entity.MessagesEntity{
+		Phone:    "+905551234567",
+		Content:  "Good morning",
+		SendAt:   "2030-01-02T09:00:00+03:00",
+		Priority: " High",
+	}
+
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.SendAt == "2030-01-02T06:00:00Z" && msg.Priority == priority.HIGH
+	})).Return(nil)
+
+	err := service.CreateMessage(ctx, message)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMessage_ValidationError(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name     string
+		phone    string
+		content  string
+		sendAt   string
+		priority string
+		tags     []string
+	}{
+		{name: "missing plus prefix", phone: "905551234567", content: "Hello"},
+		{name: "letters in phone", phone: "+90555abc4567", content: "Hello"},
+		{name: "empty content", phone: "+905551234567", content: "   "},
+		{name: "content too long", phone: "+905551234567", content: strings.Repeat("a", 101)},
+		{name: "send time without zone", phone: "+905551234567", content: "Hello", sendAt: "2030-01-02 09:00"},
+		{name: "unknown priority", phone: "+905551234567", content: "Hello", priority: "urgent"},
+		{name: "blank tag", phone: "+905551234567", content: "Hello", tags: []string{"otp", " "}},
+		{name: "tag too long", phone: "+905551234567", content: "Hello", tags: []string{strings.Repeat("t", 51)}},
+		{name: "too many tags", phone: "+905551234567", content: "Hello", tags: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.CreateMessage(ctx, &entity.MessagesEntity{
+				Phone:    tc.phone,
+				Content:  tc.content,
+				SendAt:   tc.sendAt,
+				Priority: priority.MessagePriority(tc.priority),
+				Tags:     tc.tags,
+			})
+
+			var validationErr port.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateMessage_RepositoryError(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+
+	mockRepo.On("Create", ctx, mock.Anything).Return(fmt.Errorf("database error"))
+
+	err := service.CreateMessage(ctx, &entity.MessagesEntity{Phone: "+905551234567", Content: "Hello"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create message")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMessages_PartialSuccess(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+	messages := []*entity.MessagesEntity{
+		{Phone: "+905551234567", Content: "First"},
+		{Phone: "invalid", Content: "Second"},
+		{Phone: "+905551234568", Content: "Third"},
+	}
+
+	mockRepo.On("SaveBatch", ctx, mock.MatchedBy(func(batch []*entity.MessagesEntity) bool {
+		return len(batch) == 2 && batch[0] == messages[0] && batch[1] == messages[2]
+	})).Run(func(args mock.Arguments) {
+		for i, msg := range args.Get(1).([]*entity.MessagesEntity) {
+			msg.Id = fmt.Sprint(i + 1)
+		}
+	}).Return(nil)
+
+	results, err := service.CreateMessages(ctx, messages)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "1", results[0].Message.Id)
+	assert.ErrorAs(t, results[1].Err, &port.ValidationError{})
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, "2", results[2].Message.Id)
+	assert.Equal(t, status.UNSENT, results[2].Message.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMessages_AllInvalid(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	results, err := service.CreateMessages(context.Background(), []*entity.MessagesEntity{
+		{Phone: "+905551234567", Content: ""},
+		nil,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Error(t, results[0].Err)
+	assert.Error(t, results[1].Err)
+	mockRepo.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything)
+}
+
+func TestCreateMessages_RepositoryError(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+
+	mockRepo.On("SaveBatch", ctx, mock.Anything).Return(fmt.Errorf("database error"))
+
+	results, err := service.CreateMessages(ctx, []*entity.MessagesEntity{{Phone: "+905551234567", Content: "Hello"}})
+
+	assert.Error(t, err)
+	assert.Nil(t, results)
+	assert.Contains(t, err.Error(), "failed to create messages")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_SendFailureSchedulesRetry(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, Multiplier: 2}))
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, fmt.Errorf("status_code=503"))
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.RETRYING && msg.Attempts == 1 && msg.LastError == "status_code=503" && msg.NextAttemptAt != ""
+	}), mock.Anything).Return(true, nil).Once()
+
+	_, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	nextAttemptAt, parseErr := time.Parse(time.RFC3339, message.NextAttemptAt)
+	assert.NoError(t, parseErr)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), nextAttemptAt, 5*time.Second)
+	mockRepo.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_MaxAttemptsMovesToDead(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, Multiplier: 2}))
+
+	ctx := context.Background()
+	message := createTestMessage(status.RETRYING)
+	message.Attempts = 2
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, fmt.Errorf("timeout"))
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.DEAD && msg.Attempts == 3 && msg.NextAttemptAt == ""
+	}), mock.Anything).Return(true, nil).Once()
+
+	_, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_PermanentFailure(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, port.ValidationError{Msg: "invalid recipient"})
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.FAILED && msg.Attempts == 1 && msg.LastError == "invalid recipient"
+	}), mock.Anything).Return(true, nil).Once()
+
+	_, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_WebhookErrorClassification(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus status.MessageStatus
+	}{
+		{
+			name:           "4xx fails permanently",
+			err:            port.ValidationError{Msg: "webhook rejected message", WrappedErr: &webhook.Error{Cause: webhook.ProtocolCause, StatusCode: 400}},
+			expectedStatus: status.FAILED,
+		},
+		{
+			name:           "5xx is retried",
+			err:            port.DependencyError{Msg: "webhook unavailable", WrappedErr: &webhook.Error{Cause: webhook.ProtocolCause, StatusCode: 503}},
+			expectedStatus: status.RETRYING,
+		},
+		{
+			name:           "timeout is retried",
+			err:            port.DependencyError{Msg: "webhook request failed", WrappedErr: &webhook.Error{Cause: webhook.NetworkCause, Timeout: true}},
+			expectedStatus: status.RETRYING,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockWebhook := &mocks.WebhookClientMock{}
+			mockRepo := &mocks.MessagesRepositoryMock{}
+
+			service := NewMessageSendService(mockWebhook, mockRepo, nil)
+
+			ctx := context.Background()
+			message := createTestMessage(status.UNSENT)
+
+			mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+			mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, tc.err)
+			mockRepo.On("SaveClaimed", ctx, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+			_, err := service.ProcessUnsentMessages(ctx, 1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, message.Status)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessUnsentMessages_RetryAfterPostponesNextAttempt(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, nil, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, Multiplier: 2}))
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+	rateLimited := port.DependencyError{
+		Msg:        "webhook unavailable",
+		WrappedErr: &webhook.Error{Cause: webhook.ProtocolCause, StatusCode: 429, RetryAfter: 10 * time.Minute},
+	}
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, rateLimited)
+	mockRepo.On("SaveClaimed", ctx, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	_, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.RETRYING, message.Status)
+	nextAttemptAt, parseErr := time.Parse(time.RFC3339, message.NextAttemptAt)
+	assert.NoError(t, parseErr)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), nextAttemptAt, 5*time.Second)
+}
+
+func TestProcessUnsentMessages_ClaimsWithLease(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, nil, WithLease("instance-a", 3*time.Minute, time.Minute))
+
+	ctx := context.Background()
+	message := createTestMessage(status.SENDING)
+	message.LeaseOwner = "instance-a"
+	message.LeaseExpiresAt = time.Now().Add(3 * time.Minute).Format(time.RFC3339)
+
+	webhookResponse := &webhook.WebhookResponse{MessageID: "webhook-msg-123"}
+
+	mockRepo.On("ClaimMessages", ctx, repository.ClaimOptions{Owner: "instance-a", Lease: 3 * time.Minute, PriorityAging: defaultPriorityAging, Limit: 5}).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(webhookResponse, nil)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.SENT && msg.LeaseOwner == "" && msg.LeaseExpiresAt == ""
+	}), "instance-a").Return(true, nil).Once()
+
+	_, err := service.ProcessUnsentMessages(ctx, 5)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_LostLeaseDoesNotOverwrite(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, nil, WithLease("instance-a", 3*time.Minute, time.Minute))
+
+	ctx := context.Background()
+	message := createTestMessage(status.SENDING)
+	message.Version = 4
+
+	mockRepo.On("ClaimMessages", ctx, repository.ClaimOptions{Owner: "instance-a", Lease: 3 * time.Minute, PriorityAging: defaultPriorityAging, Limit: 1}).Return([]*entity.MessagesEntity{message}, nil)
+	mockWebhook.On("SendMessage", ctx, message, mock.Anything).Return(nil, port.DependencyError{Msg: "webhook unavailable", WrappedErr: &webhook.Error{Cause: webhook.ProtocolCause, StatusCode: 503}})
+	// the lease expired during the send and another instance claimed the message
+	mockRepo.On("SaveClaimed", ctx, mock.Anything, "instance-a").Return(false, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Failed: 1}, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessUnsentMessages_ClaimError(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, nil)
+
+	ctx := context.Background()
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(5)).Return(nil, fmt.Errorf("database error"))
+
+	_, err := service.ProcessUnsentMessages(ctx, 5)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to claim unsent messages")
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLeaseReaperJob_Execute(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, nil)
+
+	job := &leaseReaperJob{messageService: service}
+
+	ctx := context.Background()
+
+	mockRepo.On("ReleaseExpiredLeases", ctx).Return(int64(3), nil)
+
+	err := job.Execute(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "LeaseReaper", job.Name())
+	mockRepo.AssertExpectations(t)
+}
+
+type leaderAwareSchedulerMock struct {
+	mocks.SchedulerMock
+	leader bool
+}
+
+func (s *leaderAwareSchedulerMock) IsLeader() bool {
+	return s.leader
+}
+
+func TestSchedulerStatus(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, &mocks.SchedulerMock{})
+	service.schedulerRunning = true
+
+	assert.Equal(t, SchedulerStatus{Running: true}, service.SchedulerStatus())
+
+	service = NewMessageSendService(mockWebhook, mockRepo, &leaderAwareSchedulerMock{leader: true})
+	service.schedulerRunning = true
+
+	assert.Equal(t, SchedulerStatus{Running: true, LeaderElection: true, Leader: true}, service.SchedulerStatus())
+}
+
+func TestCircuitBreakerStatus(t *testing.T) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{})
+
+	_, ok := service.CircuitBreakerStatus()
+	assert.False(t, ok)
+
+	breaker := webhook.NewCircuitBreakerClient(&mocks.WebhookClientMock{}, webhook.CircuitBreakerSettings{FailureThreshold: 3, CoolDown: time.Minute})
+	service = NewMessageSendService(breaker, mockRepo, &mocks.SchedulerMock{})
+
+	breakerStatus, ok := service.CircuitBreakerStatus()
+	assert.True(t, ok)
+	assert.Equal(t, webhook.BreakerClosed, breakerStatus.State)
+}
+
+func TestStartScheduler_SchedulesJobsOnce(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockScheduler := &mocks.SchedulerMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, mockScheduler)
+
+	mockScheduler.On("ScheduleJob", mock.Anything, mock.Anything).Return().Twice()
+	mockScheduler.On("Start", mock.Anything).Return()
+	mockScheduler.On("Stop").Return(nil)
+
+	ctx := context.Background()
+	service.StartScheduler(ctx)
+	assert.NoError(t, service.StopScheduler())
+	service.StartScheduler(ctx)
+
+	time.Sleep(10 * time.Millisecond)
+
+	assert.True(t, service.schedulerRunning)
+	mockScheduler.AssertNumberOfCalls(t, "ScheduleJob", 2)
+	mockScheduler.AssertNumberOfCalls(t, "Start", 2)
+}
+
+func TestUpdateSchedulerSettings_Success(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, nil)
+
+	assert.Equal(t, DefaultSchedulerSettings(), service.SchedulerSettings())
+
+	settings := SchedulerSettings{BatchSize: 50, Interval: 10 * time.Second, JobTimeout: time.Minute, Concurrency: 8, PreserveRecipientOrder: true, PriorityAging: time.Minute}
+	err := service.UpdateSchedulerSettings(context.Background(), settings)
+
+	assert.NoError(t, err)
+	assert.Equal(t, settings, service.SchedulerSettings())
+}
+
+func TestUpdateSchedulerSettings_ValidationError(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, nil, WithLease("instance-a", 2*time.Minute, time.Minute))
+
+	testCases := []SchedulerSettings{
+		{BatchSize: 0, Interval: time.Minute, JobTimeout: time.Minute, Concurrency: 4, PriorityAging: time.Minute},
+		{BatchSize: 5000, Interval: time.Minute, JobTimeout: time.Minute, Concurrency: 4, PriorityAging: time.Minute},
+		{BatchSize: 10, Interval: time.Millisecond, JobTimeout: time.Minute, Concurrency: 4, PriorityAging: time.Minute},
+		{BatchSize: 10, Interval: time.Minute, JobTimeout: 0, Concurrency: 4, PriorityAging: time.Minute},
+		{BatchSize: 10, Interval: time.Minute, JobTimeout: 2 * time.Minute, Concurrency: 4, PriorityAging: time.Minute},
+		{BatchSize: 10, Interval: time.Minute, JobTimeout: time.Minute, Concurrency: 0, PriorityAging: time.Minute},
+		{BatchSize: 10, Interval: time.Minute, JobTimeout: time.Minute, Concurrency: 500, PriorityAging: time.Minute},
+		{BatchSize: 10, Interval: time.Minute, JobTimeout: time.Minute, Concurrency: 4, PriorityAging: time.Millisecond},
+	}
+
+	for _, settings := range testCases {
+		err := service.UpdateSchedulerSettings(context.Background(), settings)
+
+		assert.ErrorAs(t, err, &port.ValidationError{})
+	}
+	assert.Equal(t, DefaultSchedulerSettings(), service.SchedulerSettings())
+}
+
+func TestNewMessageSendService_InvalidSettingsFallBackToDefaults(t *testing.T) {
+	settings := DefaultSchedulerSettings()
+	settings.BatchSize = -1
+
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, nil, WithSchedulerSettings(settings))
+
+	assert.Equal(t, DefaultSchedulerSettings(), service.SchedulerSettings())
+}
+
+func TestValidateSchedulerSettings(t *testing.T) {
+	settings := DefaultSchedulerSettings()
+	assert.NoError(t, ValidateSchedulerSettings(settings, time.Minute))
+
+	settings.BatchSize = 0
+	assert.ErrorAs(t, ValidateSchedulerSettings(settings, time.Minute), &port.ValidationError{})
+
+	settings = DefaultSchedulerSettings()
+	assert.ErrorContains(t, ValidateSchedulerSettings(settings, settings.JobTimeout), "jobTimeout must be shorter than the lease duration")
+}
+
+func TestUpdateSchedulerSettings_StoresForAllInstances(t *testing.T) {
+	mockStore := &mocks.SchedulerSettingsRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, nil, WithSettingsStore(mockStore))
+
+	ctx := context.Background()
+	settings := SchedulerSettings{BatchSize: 50, Interval: 10 * time.Second, JobTimeout: time.Minute, Concurrency: 8, PriorityAging: time.Minute}
+	mockStore.On("Save", ctx, &entity.SchedulerSettingsEntity{
+		BatchSize: 50, Interval: 10 * time.Second, JobTimeout: time.Minute, Concurrency: 8, PriorityAging: time.Minute,
+	}).Return(nil)
+
+	err := service.UpdateSchedulerSettings(ctx, settings)
+
+	assert.NoError(t, err)
+	assert.Equal(t, settings, service.SchedulerSettings())
+	mockStore.AssertExpectations(t)
+}
+
+func TestRefreshSchedulerSettings(t *testing.T) {
+	mockStore := &mocks.SchedulerSettingsRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, nil, WithSettingsStore(mockStore))
+
+	ctx := context.Background()
+	mockStore.On("Get", ctx).Return(nil, repository.ErrSchedulerSettingsNotFound).Once()
+
+	settings, err := service.RefreshSchedulerSettings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSchedulerSettings(), settings)
+
+	// a change stored by another instance replaces the local settings
+	mockStore.On("Get", ctx).Return(&entity.SchedulerSettingsEntity{
+		BatchSize: 20, Interval: 30 * time.Second, JobTimeout: time.Minute, Concurrency: 2, PriorityAging: time.Minute,
+	}, nil).Once()
+
+	settings, err = service.RefreshSchedulerSettings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, settings.BatchSize)
+	assert.Equal(t, settings, service.SchedulerSettings())
+
+	// invalid stored settings are ignored
+	mockStore.On("Get", ctx).Return(&entity.SchedulerSettingsEntity{BatchSize: 0}, nil).Once()
+
+	settings, err = service.RefreshSchedulerSettings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, settings.BatchSize)
+}
@@ -0,0 +1,398 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxTemplateNameLength = 100
+
+// placeholderPattern matches the {{name}} placeholders of template content, blanks inside the
+// braces are allowed.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// WithTemplates lets messages be enqueued from a stored template instead of with their final content.
+// Messages whose locale no variant matches get the variant of defaultLocale, if the template has one.
+func WithTemplates(templates repository.TemplatesRepository, defaultLocale string) Option {
+	return func(is *MessageSendService) {
+		is.templates = templates
+		is.defaultLocale = canonicalLocale(defaultLocale)
+	}
+}
+
+// CreateTemplate stores a new template as version 1.
+func (is *MessageSendService) CreateTemplate(ctx context.Context, template *entity.TemplateEntity) error {
+	if err := is.requireTemplates(); err != nil {
+		return err
+	}
+
+	template.Name = strings.TrimSpace(template.Name)
+	if err := prepareTemplate(template); err != nil {
+		log.Logger.Warn().Err(err).Str("name", template.Name).Msg("Rejected invalid template")
+		return err
+	}
+
+	err := is.templates.Create(ctx, template)
+	if errors.Is(err, repository.ErrTemplateExists) {
+		return port.ConflictError{Msg: fmt.Sprintf("template %s already exists", template.Name)}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	log.Logger.Info().Str("template_id", template.Id).Str("name", template.Name).Msg("Template created")
+	return nil
+}
+
+// UpdateTemplate stores the content, locale and variants of the template as its next version. Without
+// variants those of the previous version are carried over, apart from one for the new locale. The
+// version is rejected unless it renders every message waiting to be sent from the template, which
+// pick it up when they are rendered for sending.
+func (is *MessageSendService) UpdateTemplate(ctx context.Context, template *entity.TemplateEntity) error {
+	if err := is.requireTemplates(); err != nil {
+		return err
+	}
+
+	current, err := is.GetTemplate(ctx, template.Id, 0)
+	if err != nil {
+		return err
+	}
+
+	template.Name = current.Name
+	if template.Variants == nil {
+		template.Variants = carriedVariants(current, canonicalLocale(template.Locale))
+	}
+	if err := prepareTemplate(template); err != nil {
+		log.Logger.Warn().Err(err).Str("template_id", template.Id).Msg("Rejected invalid template version")
+		return err
+	}
+	if err := is.checkPendingRenderings(ctx, template); err != nil {
+		log.Logger.Warn().Err(err).Str("template_id", template.Id).Msg("Rejected template version that does not render waiting messages")
+		return err
+	}
+
+	err = is.templates.AddVersion(ctx, template)
+	if errors.Is(err, repository.ErrTemplateNotFound) {
+		return port.NotFoundError{Msg: fmt.Sprintf("template %s not found", template.Id)}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	log.Logger.Info().Str("template_id", template.Id).Int("version", template.Version).Msg("Template updated")
+	return nil
+}
+
+// GetTemplate returns the given version of the template, or its current version when version is 0.
+func (is *MessageSendService) GetTemplate(ctx context.Context, id string, version int) (*entity.TemplateEntity, error) {
+	if err := is.requireTemplates(); err != nil {
+		return nil, err
+	}
+
+	template, err := is.templates.GetTemplate(ctx, id, version)
+	if errors.Is(err, repository.ErrTemplateNotFound) {
+		if version != 0 {
+			return nil, port.NotFoundError{Msg: fmt.Sprintf("template %s has no version %d", id, version)}
+		}
+		return nil, port.NotFoundError{Msg: fmt.Sprintf("template %s not found", id)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (is *MessageSendService) ListTemplates(ctx context.Context) ([]*entity.TemplateEntity, error) {
+	if err := is.requireTemplates(); err != nil {
+		return nil, err
+	}
+
+	templates, err := is.templates.ListTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	return templates, nil
+}
+
+// ListTemplateVersions returns every version of the template, oldest first.
+func (is *MessageSendService) ListTemplateVersions(ctx context.Context, id string) ([]*entity.TemplateEntity, error) {
+	if err := is.requireTemplates(); err != nil {
+		return nil, err
+	}
+
+	versions, err := is.templates.ListVersions(ctx, id)
+	if errors.Is(err, repository.ErrTemplateNotFound) {
+		return nil, port.NotFoundError{Msg: fmt.Sprintf("template %s not found", id)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// DeleteTemplate removes the template with all its versions, unless messages waiting to be sent use it.
+func (is *MessageSendService) DeleteTemplate(ctx context.Context, id string) error {
+	if err := is.requireTemplates(); err != nil {
+		return err
+	}
+
+	err := is.templates.Delete(ctx, id)
+	switch {
+	case errors.Is(err, repository.ErrTemplateNotFound):
+		return port.NotFoundError{Msg: fmt.Sprintf("template %s not found", id)}
+	case errors.Is(err, repository.ErrTemplateInUse):
+		return port.ConflictError{Msg: fmt.Sprintf("template %s is used by messages waiting to be sent", id)}
+	case err != nil:
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	log.Logger.Info().Str("template_id", id).Msg("Template deleted")
+	return nil
+}
+
+// carriedVariants copies the variants of the previous version, leaving out the one for the locale
+// that the new content is written in.
+func carriedVariants(previous *entity.TemplateEntity, locale string) map[string]string {
+	variants := maps.Clone(previous.Variants)
+	delete(variants, locale)
+	return variants
+}
+
+// checkPendingRenderings renders the new version for every locale and set of variables among the
+// messages waiting to be sent from the template, so that the version cannot make them fail.
+func (is *MessageSendService) checkPendingRenderings(ctx context.Context, template *entity.TemplateEntity) error {
+	pending, err := is.templates.ListPendingRenderings(ctx, template.Id)
+	if err != nil {
+		return fmt.Errorf("failed to list messages waiting for template: %w", err)
+	}
+
+	for _, message := range pending {
+		content, err := renderTemplate(template, canonicalLocale(message.Locale), is.defaultLocale, message.Variables)
+		if err == nil && utf8.RuneCountInString(content) > maxContentLength {
+			err = fmt.Errorf("content renders longer than %d characters", maxContentLength)
+		}
+		if err != nil {
+			return port.ConflictError{Msg: fmt.Sprintf("new version does not render messages waiting to be sent with locale %q: %s", message.Locale, err)}
+		}
+	}
+	return nil
+}
+
+func (is *MessageSendService) requireTemplates() error {
+	if is.templates == nil {
+		return port.ValidationError{Msg: "message templates are not enabled"}
+	}
+	return nil
+}
+
+// prepareTemplate validates the template, brings its language tags into canonical form and collects
+// the variables of all variants.
+func prepareTemplate(template *entity.TemplateEntity) error {
+	if template.Name == "" || utf8.RuneCountInString(template.Name) > maxTemplateNameLength {
+		return port.ValidationError{Msg: fmt.Sprintf("template name must be between 1 and %d characters", maxTemplateNameLength)}
+	}
+
+	if err := validateTemplateContent("template content", template.Content); err != nil {
+		return err
+	}
+
+	template.Locale = canonicalLocale(template.Locale)
+	if template.Locale != "" && !validLocale(template.Locale) {
+		return port.ValidationError{Msg: fmt.Sprintf("template locale %q is not a language tag, e.g. en or tr-TR", template.Locale)}
+	}
+
+	variants := make(map[string]string, len(template.Variants))
+	for locale, content := range template.Variants {
+		locale = canonicalLocale(locale)
+		if !validLocale(locale) {
+			return port.ValidationError{Msg: fmt.Sprintf("variant locale %q is not a language tag, e.g. en or tr-TR", locale)}
+		}
+		if _, ok := variants[locale]; ok || locale == template.Locale {
+			return port.ValidationError{Msg: fmt.Sprintf("locale %s is given more than once", locale)}
+		}
+		if err := validateTemplateContent("variant "+locale, content); err != nil {
+			return err
+		}
+		variants[locale] = content
+	}
+	if len(variants) == 0 {
+		variants = nil
+	}
+	template.Variants = variants
+
+	template.Variables = templateVariables(template.Content)
+	for _, locale := range slices.Sorted(maps.Keys(variants)) {
+		for _, name := range templateVariables(variants[locale]) {
+			if !slices.Contains(template.Variables, name) {
+				template.Variables = append(template.Variables, name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateTemplateContent(name string, content string) error {
+	if strings.TrimSpace(content) == "" {
+		return port.ValidationError{Msg: name + " must not be empty"}
+	}
+
+	// the text around the placeholders alone must leave room for the variables
+	if utf8.RuneCountInString(placeholderPattern.ReplaceAllString(content, "")) > maxContentLength {
+		return port.ValidationError{Msg: fmt.Sprintf("%s without its variables must be at most %d characters", name, maxContentLength)}
+	}
+	return nil
+}
+
+// templateVariables returns the names of the placeholders in content, in order of first appearance.
+func templateVariables(content string) []string {
+	variables := []string{}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(content, -1) {
+		if !slices.Contains(variables, match[1]) {
+			variables = append(variables, match[1])
+		}
+	}
+	return variables
+}
+
+// templateVariant picks the content of the template for the recipient's locale. It tries the locale
+// itself and then ever shorter prefixes of it, e.g. tr-TR then tr, then the same for defaultLocale,
+// and falls back to the template's own content, whatever its locale.
+func templateVariant(template *entity.TemplateEntity, locale string, defaultLocale string) string {
+	for _, candidate := range append(localeFallbacks(locale), localeFallbacks(defaultLocale)...) {
+		if candidate == template.Locale {
+			return template.Content
+		}
+		if content, ok := template.Variants[candidate]; ok {
+			return content
+		}
+	}
+	return template.Content
+}
+
+// renderTemplate replaces the placeholders of the template's variant for the locale with the
+// variables. Every placeholder of that variant needs a value, other variables are ignored.
+func renderTemplate(template *entity.TemplateEntity, locale string, defaultLocale string, variables map[string]string) (string, error) {
+	content := templateVariant(template, locale, defaultLocale)
+
+	var missing []string
+	for _, name := range templateVariables(content) {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", port.ValidationError{Msg: fmt.Sprintf("template %s is missing variables: %s", template.Name, strings.Join(missing, ", "))}
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		return variables[placeholderPattern.FindStringSubmatch(placeholder)[1]]
+	}), nil
+}
+
+// applyTemplate renders the content of a message enqueued from a template, so it is validated like
+// any other content. Templates already fetched for the same request are taken from cache.
+func (is *MessageSendService) applyTemplate(ctx context.Context, message *entity.MessagesEntity, cache map[string]*entity.TemplateEntity) error {
+	if message.TemplateId == "" {
+		if len(message.Variables) > 0 {
+			return port.ValidationError{Msg: "variables require a templateId"}
+		}
+		return nil
+	}
+
+	if message.Content != "" {
+		return port.ValidationError{Msg: "content and templateId are mutually exclusive"}
+	}
+	if err := is.requireTemplates(); err != nil {
+		return err
+	}
+
+	template, ok := cache[message.TemplateId]
+	if !ok {
+		var err error
+		template, err = is.templates.GetTemplate(ctx, message.TemplateId, 0)
+		if errors.Is(err, repository.ErrTemplateNotFound) {
+			return port.ValidationError{Msg: fmt.Sprintf("template %s not found", message.TemplateId)}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get template: %w", err)
+		}
+		cache[message.TemplateId] = template
+	}
+
+	content, err := renderTemplate(template, canonicalLocale(message.Locale), is.defaultLocale, message.Variables)
+	if err != nil {
+		return err
+	}
+
+	message.Content = content
+	message.TemplateVersion = template.Version
+	return nil
+}
+
+// renderContent renders a templated message with the current version of its template right before
+// it is sent, so corrections to the template reach messages already waiting. Should the current
+// version not render for the message, e.g. because it needs a variable the message lacks, the message
+// is rendered with the version it was validated against when it was enqueued instead.
+func (is *MessageSendService) renderContent(ctx context.Context, message *entity.MessagesEntity) error {
+	if message.TemplateId == "" {
+		return nil
+	}
+	if err := is.requireTemplates(); err != nil {
+		return err
+	}
+
+	template, content, err := is.renderVersion(ctx, message, 0)
+	var validationErr port.ValidationError
+	if errors.As(err, &validationErr) && template != nil && message.TemplateVersion != 0 && message.TemplateVersion != template.Version {
+		log.Logger.Warn().
+			Err(err).
+			Str("message_id", message.Id).
+			Str("template_id", message.TemplateId).
+			Int("version", message.TemplateVersion).
+			Msg("Current template version does not render the message, using its pinned version")
+
+		template, content, err = is.renderVersion(ctx, message, message.TemplateVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	message.Content = content
+	message.TemplateVersion = template.Version
+	return nil
+}
+
+// renderVersion renders the message with the given version of its template, or with the current one
+// when version is 0. The template is returned along with a render error so callers know its version.
+func (is *MessageSendService) renderVersion(ctx context.Context, message *entity.MessagesEntity, version int) (*entity.TemplateEntity, string, error) {
+	template, err := is.templates.GetTemplate(ctx, message.TemplateId, version)
+	if errors.Is(err, repository.ErrTemplateNotFound) {
+		if version != 0 {
+			return nil, "", port.ValidationError{Msg: fmt.Sprintf("template %s no longer has version %d", message.TemplateId, version)}
+		}
+		return nil, "", port.ValidationError{Msg: fmt.Sprintf("template %s no longer exists", message.TemplateId)}
+	}
+	if err != nil {
+		return nil, "", port.DBFailureError{Msg: "failed to get template", WrappedErr: err}
+	}
+
+	content, err := renderTemplate(template, canonicalLocale(message.Locale), is.defaultLocale, message.Variables)
+	if err != nil {
+		return template, "", err
+	}
+	if utf8.RuneCountInString(content) > maxContentLength {
+		return template, "", port.ValidationError{Msg: fmt.Sprintf("template %s version %d renders content longer than %d characters", template.Name, template.Version, maxContentLength)}
+	}
+	return template, content, nil
+}
@@ -0,0 +1,485 @@
+package application
+
+import (
+	"context"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/attempt"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/client/webhook"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/mocks"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var otpTemplate = &entity.TemplateEntity{
+	Id:        "7",
+	Name:      "otp",
+	Version:   2,
+	Content:   "Hi {{name}}, your code is {{ code }}. Do not share {{code}}.",
+	Variables: []string{"name", "code"},
+}
+
+func newTemplatesTestService() (*MessageSendService, *mocks.MessagesRepositoryMock, *mocks.TemplatesRepositoryMock) {
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockTemplates := &mocks.TemplatesRepositoryMock{}
+
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, mockRepo, &mocks.SchedulerMock{}, WithTemplates(mockTemplates, "en"))
+	return service, mockRepo, mockTemplates
+}
+
+func TestTemplateVariables(t *testing.T) {
+	assert.Equal(t, []string{"name", "code"}, templateVariables(otpTemplate.Content))
+	assert.Equal(t, []string{}, templateVariables("No placeholders, {{ not a variable }} or {single}"))
+}
+
+func TestRenderTemplate(t *testing.T) {
+	content, err := renderTemplate(otpTemplate, "", "", map[string]string{"name": "Ayşe", "code": "4711", "unused": "x"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi Ayşe, your code is 4711. Do not share 4711.", content)
+
+	_, err = renderTemplate(otpTemplate, "", "", map[string]string{})
+	assert.ErrorAs(t, err, &port.ValidationError{})
+	assert.ErrorContains(t, err, "template otp is missing variables: name, code")
+}
+
+var localizedTemplate = &entity.TemplateEntity{
+	Id:      "9",
+	Name:    "greeting",
+	Version: 1,
+	Content: "Hello {{name}}",
+	Locale:  "en",
+	Variants: map[string]string{
+		"tr":    "Merhaba {{name}}",
+		"pt-BR": "Olá {{name}}",
+		"de":    "Hallo {{title}} {{name}}",
+	},
+}
+
+func TestCanonicalLocale(t *testing.T) {
+	assert.Equal(t, "tr-TR", canonicalLocale(" TR_tr "))
+	assert.Equal(t, "sr-Latn-RS", canonicalLocale("SR-LATN-rs"))
+	assert.Equal(t, "es-419", canonicalLocale("es-419"))
+	assert.Equal(t, "", canonicalLocale(""))
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	assert.Equal(t, []string{"sr-Latn-RS", "sr-Latn", "sr"}, localeFallbacks("sr-Latn-RS"))
+	assert.Equal(t, []string{"tr"}, localeFallbacks("tr"))
+	assert.Empty(t, localeFallbacks(""))
+}
+
+func TestRenderTemplate_PicksVariantForLocale(t *testing.T) {
+	variables := map[string]string{"name": "Ayşe"}
+
+	testCases := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "tr-TR", want: "Merhaba Ayşe"},
+		{locale: "tr", want: "Merhaba Ayşe"},
+		{locale: "pt-BR", want: "Olá Ayşe"},
+		{locale: "pt-PT", want: "Hello Ayşe"},
+		{locale: "en-GB", want: "Hello Ayşe"},
+		{locale: "fr", want: "Hello Ayşe"},
+		{locale: "", want: "Hello Ayşe"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.locale, func(t *testing.T) {
+			content, err := renderTemplate(localizedTemplate, tc.locale, "", variables)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, content)
+		})
+	}
+
+	// only the placeholders of the chosen variant are required
+	_, err := renderTemplate(localizedTemplate, "de-AT", "", variables)
+	assert.ErrorContains(t, err, "template greeting is missing variables: title")
+}
+
+func TestRenderTemplate_FallsBackToDefaultLocale(t *testing.T) {
+	template := &entity.TemplateEntity{
+		Name:     "greeting",
+		Content:  "Merhaba {{name}}",
+		Variants: map[string]string{"en": "Hello {{name}}"},
+	}
+	variables := map[string]string{"name": "Ayşe"}
+
+	content, err := renderTemplate(template, "fr-FR", "en", variables)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello Ayşe", content)
+
+	content, err = renderTemplate(template, "fr-FR", "", variables)
+	assert.NoError(t, err)
+	assert.Equal(t, "Merhaba Ayşe", content)
+}
+
+func TestCreateTemplate_CollectsVariables(t *testing.T) {
+	service, _, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("Create", ctx, mock.MatchedBy(func(template *entity.TemplateEntity) bool {
+		return template.Name == "welcome" && assert.ObjectsAreEqual([]string{"name"}, template.Variables)
+	})).Run(func(args mock.Arguments) {
+		template := args.Get(1).(*entity.TemplateEntity)
+		template.Id = "1"
+		template.Version = 1
+	}).Return(nil)
+
+	template := &entity.TemplateEntity{Name: " welcome ", Content: "Welcome {{name}}!"}
+	err := service.CreateTemplate(ctx, template)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, template.Version)
+	mockTemplates.AssertExpectations(t)
+}
+
+func TestCreateTemplate_NormalizesVariants(t *testing.T) {
+	service, _, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("Create", ctx, mock.Anything).Return(nil)
+
+	template := &entity.TemplateEntity{
+		Name:     "greeting",
+		Content:  "Hello {{name}}",
+		Locale:   "EN",
+		Variants: map[string]string{"tr_tr": "Merhaba {{name}}", "de": "Hallo {{title}} {{name}}"},
+	}
+	err := service.CreateTemplate(ctx, template)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "en", template.Locale)
+	assert.Equal(t, map[string]string{"tr-TR": "Merhaba {{name}}", "de": "Hallo {{title}} {{name}}"}, template.Variants)
+	assert.Equal(t, []string{"name", "title"}, template.Variables)
+}
+
+func TestCreateTemplate_Rejected(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template *entity.TemplateEntity
+		wantErr  string
+	}{
+		{name: "no name", template: &entity.TemplateEntity{Content: "Hi"}, wantErr: "template name must be between 1 and 100 characters"},
+		{name: "no content", template: &entity.TemplateEntity{Name: "empty", Content: " "}, wantErr: "template content must not be empty"},
+		{name: "text too long", template: &entity.TemplateEntity{Name: "long", Content: strings.Repeat("a", 101) + "{{name}}"}, wantErr: "without its variables must be at most 100 characters"},
+		{name: "invalid locale", template: &entity.TemplateEntity{Name: "hi", Content: "Hi", Locale: "english"}, wantErr: `template locale "english" is not a language tag`},
+		{name: "invalid variant locale", template: &entity.TemplateEntity{Name: "hi", Content: "Hi", Variants: map[string]string{"tr TR": "Selam"}}, wantErr: `variant locale "tr tr" is not a language tag`},
+		{name: "variant of the default locale", template: &entity.TemplateEntity{Name: "hi", Content: "Hi", Locale: "en", Variants: map[string]string{"EN": "Hello"}}, wantErr: "locale en is given more than once"},
+		{name: "empty variant", template: &entity.TemplateEntity{Name: "hi", Content: "Hi", Variants: map[string]string{"tr": ""}}, wantErr: "variant tr must not be empty"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, _, mockTemplates := newTemplatesTestService()
+
+			err := service.CreateTemplate(context.Background(), tc.template)
+
+			assert.ErrorAs(t, err, &port.ValidationError{})
+			assert.ErrorContains(t, err, tc.wantErr)
+			mockTemplates.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateTemplate_DuplicateName(t *testing.T) {
+	service, _, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("Create", ctx, mock.Anything).Return(repository.ErrTemplateExists)
+
+	err := service.CreateTemplate(ctx, &entity.TemplateEntity{Name: "otp", Content: "{{code}}"})
+
+	assert.ErrorAs(t, err, &port.ConflictError{})
+}
+
+func TestUpdateTemplate_AddsVersion(t *testing.T) {
+	service, _, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("GetTemplate", ctx, "7", 0).Return(otpTemplate, nil)
+	mockTemplates.On("ListPendingRenderings", ctx, "7").Return([]*entity.MessagesEntity{
+		{Variables: map[string]string{"name": "Ayşe", "code": "4711"}},
+	}, nil)
+	mockTemplates.On("AddVersion", ctx, mock.MatchedBy(func(template *entity.TemplateEntity) bool {
+		return template.Id == "7" && assert.ObjectsAreEqual([]string{"code"}, template.Variables)
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.TemplateEntity).Version = 3
+	}).Return(nil)
+
+	template := &entity.TemplateEntity{Id: "7", Content: "Your code: {{code}}"}
+	err := service.UpdateTemplate(ctx, template)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, template.Version)
+	mockTemplates.AssertExpectations(t)
+}
+
+func TestUpdateTemplate_CarriesVariantsOver(t *testing.T) {
+	testCases := []struct {
+		name     string
+		locale   string
+		variants map[string]string
+		want     map[string]string
+	}{
+		{name: "variants left out", locale: "en", want: localizedTemplate.Variants},
+		{name: "content takes over a variant", locale: "TR", want: map[string]string{"pt-BR": "Olá {{name}}", "de": "Hallo {{title}} {{name}}"}},
+		{name: "variants replaced", locale: "en", variants: map[string]string{"tr": "Selam {{name}}"}, want: map[string]string{"tr": "Selam {{name}}"}},
+		{name: "variants removed", locale: "en", variants: map[string]string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, _, mockTemplates := newTemplatesTestService()
+
+			ctx := context.Background()
+			mockTemplates.On("GetTemplate", ctx, "9", 0).Return(localizedTemplate, nil)
+			mockTemplates.On("ListPendingRenderings", ctx, "9").Return([]*entity.MessagesEntity{}, nil)
+			mockTemplates.On("AddVersion", ctx, mock.Anything).Return(nil)
+
+			template := &entity.TemplateEntity{Id: "9", Content: "Hi {{name}}", Locale: tc.locale, Variants: tc.variants}
+			err := service.UpdateTemplate(ctx, template)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, template.Variants)
+			mockTemplates.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateTemplate_RejectsVersionThatDoesNotRenderWaitingMessages(t *testing.T) {
+	service, _, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("GetTemplate", ctx, "9", 0).Return(localizedTemplate, nil)
+	mockTemplates.On("ListPendingRenderings", ctx, "9").Return([]*entity.MessagesEntity{
+		{Locale: "en", Variables: map[string]string{"name": "Ayşe"}},
+		{Locale: "tr-TR", Variables: map[string]string{"name": "Ayşe"}},
+	}, nil)
+
+	template := &entity.TemplateEntity{Id: "9", Content: "Hello {{name}}", Locale: "en", Variants: map[string]string{"tr": "Merhaba {{title}} {{name}}"}}
+	err := service.UpdateTemplate(ctx, template)
+
+	assert.ErrorAs(t, err, &port.ConflictError{})
+	assert.ErrorContains(t, err, "missing variables: title")
+	mockTemplates.AssertNotCalled(t, "AddVersion", mock.Anything, mock.Anything)
+}
+
+func TestDeleteTemplate_InUse(t *testing.T) {
+	service, _, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("Delete", ctx, "7").Return(repository.ErrTemplateInUse)
+
+	err := service.DeleteTemplate(ctx, "7")
+
+	assert.ErrorAs(t, err, &port.ConflictError{})
+}
+
+func TestCreateMessage_RendersTemplate(t *testing.T) {
+	service, mockRepo, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("GetTemplate", ctx, "7", 0).Return(otpTemplate, nil).Once()
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Content == "Hi Ayşe, your code is 4711. Do not share 4711." && msg.TemplateId == "7" && msg.TemplateVersion == 2
+	})).Return(nil)
+
+	message := &entity.MessagesEntity{Phone: "+905551234567", TemplateId: "7", Variables: map[string]string{"name": "Ayşe", "code": "4711"}}
+	err := service.CreateMessage(ctx, message)
+
+	assert.NoError(t, err)
+	assert.Equal(t, status.UNSENT, message.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMessage_InvalidTemplateUse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		message *entity.MessagesEntity
+		wantErr string
+	}{
+		{name: "missing variable", message: &entity.MessagesEntity{TemplateId: "7", Variables: map[string]string{"name": "Ayşe"}}, wantErr: "missing variables: code"},
+		{name: "content too long", message: &entity.MessagesEntity{TemplateId: "7", Variables: map[string]string{"name": strings.Repeat("a", 60), "code": "4711"}}, wantErr: "content must be at most 100 characters"},
+		{name: "content and template", message: &entity.MessagesEntity{TemplateId: "7", Content: "Hi"}, wantErr: "mutually exclusive"},
+		{name: "variables without template", message: &entity.MessagesEntity{Content: "Hi", Variables: map[string]string{"name": "Ayşe"}}, wantErr: "variables require a templateId"},
+		{name: "unknown template", message: &entity.MessagesEntity{TemplateId: "8"}, wantErr: "template 8 not found"},
+		{name: "invalid locale", message: &entity.MessagesEntity{TemplateId: "7", Variables: map[string]string{"name": "Ayşe", "code": "4711"}, Locale: "turkish"}, wantErr: "locale must be a language tag"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, mockRepo, mockTemplates := newTemplatesTestService()
+
+			ctx := context.Background()
+			mockTemplates.On("GetTemplate", ctx, "7", 0).Return(otpTemplate, nil)
+			mockTemplates.On("GetTemplate", ctx, "8", 0).Return(nil, repository.ErrTemplateNotFound)
+			tc.message.Phone = "+905551234567"
+
+			err := service.CreateMessage(ctx, tc.message)
+
+			assert.ErrorAs(t, err, &port.ValidationError{})
+			assert.ErrorContains(t, err, tc.wantErr)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateMessage_RendersVariantForLocale(t *testing.T) {
+	service, mockRepo, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("GetTemplate", ctx, "9", 0).Return(localizedTemplate, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Content == "Merhaba Ayşe" && msg.Locale == "tr-TR"
+	})).Return(nil)
+
+	message := &entity.MessagesEntity{Phone: "+905551234567", TemplateId: "9", Variables: map[string]string{"name": "Ayşe"}, Locale: "tr_tr"}
+	err := service.CreateMessage(ctx, message)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMessages_FetchesEachTemplateOnce(t *testing.T) {
+	service, mockRepo, mockTemplates := newTemplatesTestService()
+
+	ctx := context.Background()
+	mockTemplates.On("GetTemplate", ctx, "7", 0).Return(otpTemplate, nil).Once()
+	mockRepo.On("SaveBatch", ctx, mock.MatchedBy(func(messages []*entity.MessagesEntity) bool {
+		return len(messages) == 2
+	})).Return(nil)
+
+	results, err := service.CreateMessages(ctx, []*entity.MessagesEntity{
+		{Phone: "+905551234567", TemplateId: "7", Variables: map[string]string{"name": "Ayşe", "code": "1"}},
+		{Phone: "+905551234568", TemplateId: "7", Variables: map[string]string{"name": "Can"}},
+		{Phone: "+905551234569", TemplateId: "7", Variables: map[string]string{"name": "Elif", "code": "3"}},
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorContains(t, results[1].Err, "missing variables: code")
+	assert.NoError(t, results[2].Err)
+	mockTemplates.AssertExpectations(t)
+}
+
+func TestUpdateMessage_ContentReplacesTemplate(t *testing.T) {
+	service, mockRepo, _ := newTemplatesTestService()
+
+	ctx := context.Background()
+	message := createPendingMessage()
+	message.TemplateId = "7"
+	message.TemplateVersion = 2
+	message.Variables = map[string]string{"code": "4711"}
+	mockRepo.On("GetMessage", ctx, "42").Return(message, nil)
+	mockRepo.On("UpdatePending", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Content == "Plain text" && msg.TemplateId == "" && msg.TemplateVersion == 0 && msg.Variables == nil
+	})).Return(true, nil)
+
+	content := "Plain text"
+	_, err := service.UpdateMessage(ctx, "42", MessageChanges{Content: &content})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendMessage_RendersCurrentTemplateVersion(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	service, mockRepo, mockTemplates := newTemplatesTestService()
+	service.client = mockWebhook
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+	message.Content = "Hi Ayşe, your code is 4711. Do not share 4711."
+	message.TemplateId = "7"
+	message.TemplateVersion = 2
+	message.Variables = map[string]string{"name": "Ayşe", "code": "4711"}
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockTemplates.On("GetTemplate", ctx, "7", 0).Return(&entity.TemplateEntity{
+		Id: "7", Name: "otp", Version: 3, Content: "{{code}} is your code", Variables: []string{"code"},
+	}, nil)
+	mockWebhook.On("SendMessage", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Content == "4711 is your code"
+	}), mock.Anything).Return(&webhook.WebhookResponse{MessageID: "remote-1"}, nil)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.SENT && msg.Content == "4711 is your code" && msg.TemplateVersion == 3
+	}), mock.Anything).Return(true, nil)
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Succeeded: 1}, result)
+	mockWebhook.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendMessage_TemplateNoLongerRenders(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockAttempts := &mocks.SendAttemptsRepositoryMock{}
+	service, mockRepo, mockTemplates := newTemplatesTestService()
+	service.client = mockWebhook
+	service.sendAttempts = mockAttempts
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+	message.TemplateId = "7"
+	message.Variables = map[string]string{"name": "Ayşe"}
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockAttempts.On("Begin", ctx, mock.Anything).Return(&entity.SendAttemptEntity{MessageId: message.Id, Attempt: 1, Status: attempt.PENDING}, nil)
+	mockTemplates.On("GetTemplate", ctx, "7", 0).Return(otpTemplate, nil)
+	mockAttempts.On("Finish", ctx, mock.MatchedBy(func(a *entity.SendAttemptEntity) bool {
+		return a.Status == attempt.FAILED
+	})).Return(nil)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.FAILED && strings.Contains(msg.LastError, "missing variables: code")
+	}), mock.Anything).Return(true, nil)
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Failed: 1}, result)
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+	mockAttempts.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendMessage_FallsBackToPinnedTemplateVersion(t *testing.T) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	service, mockRepo, mockTemplates := newTemplatesTestService()
+	service.client = mockWebhook
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+	message.TemplateId = "7"
+	message.TemplateVersion = 2
+	message.Variables = map[string]string{"name": "Ayşe", "code": "4711"}
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockTemplates.On("GetTemplate", ctx, "7", 0).Return(&entity.TemplateEntity{
+		Id: "7", Name: "otp", Version: 3, Content: "{{code}} expires at {{expiry}}", Variables: []string{"code", "expiry"},
+	}, nil)
+	mockTemplates.On("GetTemplate", ctx, "7", 2).Return(otpTemplate, nil)
+	mockWebhook.On("SendMessage", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Content == "Hi Ayşe, your code is 4711. Do not share 4711."
+	}), mock.Anything).Return(&webhook.WebhookResponse{MessageID: "remote-1"}, nil)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.SENT && msg.TemplateVersion == 2
+	}), mock.Anything).Return(true, nil)
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Succeeded: 1}, result)
+	mockTemplates.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/port"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxContentLength mirrors the CHECK constraint on messages.content in local/init.sql.
+const maxContentLength = 100
+
+const (
+	maxTags      = 10
+	maxTagLength = 50
+)
+
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// localePattern accepts BCP 47 language tags in the simple form used for recipients: a language
+// followed by optional script and region subtags, e.g. tr, tr-TR or sr-Latn-RS.
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+func validateMessage(message *entity.MessagesEntity) error {
+	if !phonePattern.MatchString(message.Phone) {
+		return port.ValidationError{Msg: "phone must be in E.164 format, e.g. +905551234567"}
+	}
+
+	if strings.TrimSpace(message.Content) == "" {
+		return port.ValidationError{Msg: "content must not be empty"}
+	}
+
+	if utf8.RuneCountInString(message.Content) > maxContentLength {
+		return port.ValidationError{Msg: "content must be at most 100 characters"}
+	}
+
+	if !message.Priority.Valid() {
+		return port.ValidationError{Msg: "priority must be one of critical, high, normal or bulk"}
+	}
+
+	if len(message.Tags) > maxTags {
+		return port.ValidationError{Msg: fmt.Sprintf("at most %d tags are allowed", maxTags)}
+	}
+
+	for _, tag := range message.Tags {
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return port.ValidationError{Msg: fmt.Sprintf("tags must be between 1 and %d characters", maxTagLength)}
+		}
+	}
+
+	if message.Locale != "" && !validLocale(message.Locale) {
+		return port.ValidationError{Msg: "locale must be a language tag, e.g. tr-TR"}
+	}
+
+	if message.SendAt != "" {
+		if _, err := time.Parse(time.RFC3339, message.SendAt); err != nil {
+			return port.ValidationError{Msg: "sendAt must be an RFC 3339 timestamp, e.g. 2025-01-02T09:00:00+03:00"}
+		}
+	}
+
+	return nil
+}
+
+func validLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// canonicalLocale brings a language tag into its usual letter case, e.g. TR_tr becomes tr-TR and
+// sr-latn-rs becomes sr-Latn-RS, so tags given in any form select the same template variant.
+func canonicalLocale(locale string) string {
+	subtags := strings.Split(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	for i, subtag := range subtags {
+		switch {
+		case i == 0:
+			subtags[i] = strings.ToLower(subtag)
+		case len(subtag) == 2 && isLetters(subtag):
+			subtags[i] = strings.ToUpper(subtag)
+		case len(subtag) == 4 && isLetters(subtag):
+			subtags[i] = strings.ToUpper(subtag[:1]) + strings.ToLower(subtag[1:])
+		default:
+			subtags[i] = strings.ToLower(subtag)
+		}
+	}
+	return strings.Join(subtags, "-")
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// localeFallbacks lists the locale followed by ever shorter prefixes of it, e.g. sr-Latn-RS,
+// sr-Latn and sr. It is empty when no locale is given.
+func localeFallbacks(locale string) []string {
+	var fallbacks []string
+	for locale != "" {
+		fallbacks = append(fallbacks, locale)
+		end := strings.LastIndexByte(locale, '-')
+		if end < 0 {
+			break
+		}
+		locale = locale[:end]
+	}
+	return fallbacks
+}
@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"message-scheduler/internal/infra/client/sink"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"time"
+)
+
+const (
+	defaultOutboxBatchSize  = 100
+	defaultOutboxInterval   = 5 * time.Second
+	defaultOutboxRetryDelay = 30 * time.Second
+)
+
+// OutboxSettings control the relay that publishes outbox events.
+type OutboxSettings struct {
+	BatchSize int
+	Interval  time.Duration
+	// RetryDelay holds back a batch whose publishing failed before it is tried again.
+	RetryDelay time.Duration
+	// Retention is how long published events are kept, 0 keeps them forever.
+	Retention time.Duration
+}
+
+type outboxRelay struct {
+	repo      repository.OutboxRepository
+	sink      sink.EventSink
+	settings  OutboxSettings
+	scheduler port.Scheduler
+	running   bool
+	started   bool
+}
+
+// WithOutbox publishes the status transitions the database records in the outbox to eventSink.
+// Without it the events pile up unpublished. The relay runs on relayScheduler, apart from the
+// message sender, so stopping the sender does not hold back the events of messages already sent.
+func WithOutbox(outboxRepo repository.OutboxRepository, eventSink sink.EventSink, relayScheduler port.Scheduler, settings OutboxSettings) Option {
+	return func(is *MessageSendService) {
+		if settings.BatchSize <= 0 {
+			settings.BatchSize = defaultOutboxBatchSize
+		}
+		if settings.Interval <= 0 {
+			settings.Interval = defaultOutboxInterval
+		}
+		if settings.RetryDelay <= 0 {
+			settings.RetryDelay = defaultOutboxRetryDelay
+		}
+		is.outbox = &outboxRelay{repo: outboxRepo, sink: eventSink, settings: settings, scheduler: relayScheduler}
+	}
+}
+
+// StartOutboxRelay starts publishing outbox events every Interval until StopOutboxRelay is called.
+func (is *MessageSendService) StartOutboxRelay(ctx context.Context) {
+	if is.outbox == nil || is.outbox.running {
+		return
+	}
+
+	// the job stays registered with the scheduler across stop/start cycles
+	if !is.outbox.started {
+		is.outbox.scheduler.ScheduleJob(&outboxRelayJob{messageService: is}, is.outbox.settings.Interval)
+		is.outbox.started = true
+	}
+
+	go is.outbox.scheduler.Start(ctx)
+
+	is.outbox.running = true
+	log.Logger.Info().Msg("Outbox relay started")
+}
+
+func (is *MessageSendService) StopOutboxRelay() error {
+	if is.outbox == nil || !is.outbox.running {
+		return nil
+	}
+
+	if err := is.outbox.scheduler.Stop(); err != nil {
+		return fmt.Errorf("failed to stop outbox relay: %w", err)
+	}
+
+	is.outbox.running = false
+	log.Logger.Info().Msg("Outbox relay stopped")
+	return nil
+}
+
+// RelayOutboxEvents publishes the next batch of outbox events and returns how many were published.
+// Events are leased while they are published, so relays of several instances never publish the
+// same batch concurrently.
+func (is *MessageSendService) RelayOutboxEvents(ctx context.Context) (int, error) {
+	if is.outbox == nil {
+		return 0, nil
+	}
+
+	events, err := is.outbox.repo.ClaimEvents(ctx, is.leaseOwner, is.leaseDuration, is.outbox.settings.BatchSize)
+	if err != nil {
+		return 0, port.DBFailureError{Msg: "failed to claim outbox events", WrappedErr: err}
+	}
+
+	if len(events) > 0 {
+		ids := make([]string, len(events))
+		for i, event := range events {
+			ids[i] = event.Id
+		}
+
+		if err := is.outbox.sink.Publish(ctx, events); err != nil {
+			log.Logger.Warn().Err(err).Int("events", len(events)).Dur("retry_after", is.outbox.settings.RetryDelay).Msg("Failed to publish outbox events")
+
+			// the lease would release the events as well, just later
+			if releaseErr := is.outbox.repo.ReleaseEvents(ctx, ids, is.outbox.settings.RetryDelay, err.Error()); releaseErr != nil {
+				log.Logger.Error().Err(releaseErr).Msg("Failed to release unpublished outbox events")
+			}
+			return 0, port.DependencyError{Msg: "failed to publish outbox events", WrappedErr: err}
+		}
+
+		// if this fails the events are published again once their lease expires
+		if err := is.outbox.repo.MarkPublished(ctx, ids); err != nil {
+			return len(events), port.DBFailureError{Msg: "failed to mark outbox events published", WrappedErr: err}
+		}
+
+		log.Logger.Info().Int("events", len(events)).Msg("Published outbox events")
+	}
+
+	if is.outbox.settings.Retention > 0 {
+		if deleted, err := is.outbox.repo.DeletePublished(ctx, is.outbox.settings.Retention); err != nil {
+			log.Logger.Error().Err(err).Msg("Failed to delete old outbox events")
+		} else if deleted > 0 {
+			log.Logger.Info().Int64("deleted_events", deleted).Msg("Deleted old outbox events")
+		}
+	}
+
+	return len(events), nil
+}
+
+type outboxRelayJob struct {
+	messageService *MessageSendService
+}
+
+func (j *outboxRelayJob) Execute(ctx context.Context) error {
+	_, err := j.messageService.RelayOutboxEvents(ctx)
+	return err
+}
+
+func (j *outboxRelayJob) Name() string {
+	return "OutboxRelay"
+}
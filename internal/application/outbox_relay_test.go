@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/event"
+	"message-scheduler/internal/port"
+	"message-scheduler/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func createOutboxEvents() []*entity.OutboxEventEntity {
+	return []*entity.OutboxEventEntity{
+		{Id: "1", Type: event.MESSAGE_SENT, MessageId: "42", Payload: json.RawMessage(`{"status":"sent"}`)},
+		{Id: "2", Type: event.MESSAGE_DELIVERED, MessageId: "42", Payload: json.RawMessage(`{"status":"delivered"}`)},
+	}
+}
+
+func TestRelayOutboxEvents_PublishesClaimedEvents(t *testing.T) {
+	mockOutbox := &mocks.OutboxRepositoryMock{}
+	mockSink := &mocks.EventSinkMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, &mocks.SchedulerMock{},
+		WithLease("instance-1", time.Minute, time.Minute),
+		WithOutbox(mockOutbox, mockSink, &mocks.SchedulerMock{}, OutboxSettings{BatchSize: 10}))
+
+	ctx := context.Background()
+	events := createOutboxEvents()
+	mockOutbox.On("ClaimEvents", ctx, "instance-1", time.Minute, 10).Return(events, nil)
+	mockSink.On("Publish", ctx, events).Return(nil).Once()
+	mockOutbox.On("MarkPublished", ctx, []string{"1", "2"}).Return(nil).Once()
+
+	published, err := service.RelayOutboxEvents(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	mockSink.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+	mockOutbox.AssertNotCalled(t, "DeletePublished", mock.Anything, mock.Anything)
+}
+
+func TestRelayOutboxEvents_ReleasesEventsWhenSinkFails(t *testing.T) {
+	mockOutbox := &mocks.OutboxRepositoryMock{}
+	mockSink := &mocks.EventSinkMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, &mocks.SchedulerMock{},
+		WithOutbox(mockOutbox, mockSink, &mocks.SchedulerMock{}, OutboxSettings{RetryDelay: time.Minute}))
+
+	ctx := context.Background()
+	events := createOutboxEvents()
+	mockOutbox.On("ClaimEvents", ctx, mock.Anything, mock.Anything, defaultOutboxBatchSize).Return(events, nil)
+	mockSink.On("Publish", ctx, events).Return(fmt.Errorf("connection refused"))
+	mockOutbox.On("ReleaseEvents", ctx, []string{"1", "2"}, time.Minute, "connection refused").Return(nil).Once()
+
+	published, err := service.RelayOutboxEvents(ctx)
+
+	assert.ErrorAs(t, err, &port.DependencyError{})
+	assert.Equal(t, 0, published)
+	mockOutbox.AssertExpectations(t)
+	mockOutbox.AssertNotCalled(t, "MarkPublished", mock.Anything, mock.Anything)
+}
+
+func TestRelayOutboxEvents_NothingToPublish(t *testing.T) {
+	mockOutbox := &mocks.OutboxRepositoryMock{}
+	mockSink := &mocks.EventSinkMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, &mocks.SchedulerMock{},
+		WithOutbox(mockOutbox, mockSink, &mocks.SchedulerMock{}, OutboxSettings{Retention: 24 * time.Hour}))
+
+	ctx := context.Background()
+	mockOutbox.On("ClaimEvents", ctx, mock.Anything, mock.Anything, mock.Anything).Return([]*entity.OutboxEventEntity{}, nil)
+	mockOutbox.On("DeletePublished", ctx, 24*time.Hour).Return(int64(3), nil).Once()
+
+	published, err := service.RelayOutboxEvents(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	mockSink.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestRelayOutboxEvents_ClaimFailure(t *testing.T) {
+	mockOutbox := &mocks.OutboxRepositoryMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, &mocks.SchedulerMock{},
+		WithOutbox(mockOutbox, &mocks.EventSinkMock{}, &mocks.SchedulerMock{}, OutboxSettings{}))
+
+	ctx := context.Background()
+	mockOutbox.On("ClaimEvents", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("database error"))
+
+	_, err := service.RelayOutboxEvents(ctx)
+
+	assert.ErrorAs(t, err, &port.DBFailureError{})
+}
+
+func TestStartOutboxRelay_RunsApartFromTheSender(t *testing.T) {
+	senderScheduler := &mocks.SchedulerMock{}
+	relayScheduler := &mocks.SchedulerMock{}
+	service := NewMessageSendService(&mocks.WebhookClientMock{}, &mocks.MessagesRepositoryMock{}, senderScheduler,
+		WithOutbox(&mocks.OutboxRepositoryMock{}, &mocks.EventSinkMock{}, relayScheduler, OutboxSettings{Interval: time.Minute}))
+
+	senderScheduler.On("ScheduleJob", mock.Anything, mock.Anything).Return()
+	senderScheduler.On("Start", mock.Anything).Return()
+	senderScheduler.On("Stop").Return(nil)
+	relayScheduler.On("ScheduleJob", mock.AnythingOfType("*application.outboxRelayJob"), time.Minute).Return().Once()
+	relayScheduler.On("Start", mock.Anything).Return()
+
+	ctx := context.Background()
+	service.StartScheduler(ctx)
+	service.StartOutboxRelay(ctx)
+	assert.NoError(t, service.StopScheduler())
+
+	time.Sleep(10 * time.Millisecond)
+
+	senderScheduler.AssertNotCalled(t, "ScheduleJob", mock.AnythingOfType("*application.outboxRelayJob"), mock.Anything)
+	relayScheduler.AssertExpectations(t)
+	relayScheduler.AssertNotCalled(t, "Stop")
+}
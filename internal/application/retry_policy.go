@@ -0,0 +1,105 @@
+package application
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/client/webhook"
+	"message-scheduler/internal/port"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBaseDelay   = 30 * time.Second
+	defaultMaxDelay    = time.Hour
+	defaultMultiplier  = 2.0
+	defaultJitter      = 0.2
+
+	// maxLastErrorLength keeps provider error bodies from bloating the messages table.
+	maxLastErrorLength = 1000
+)
+
+// RetryPolicy schedules failed deliveries with exponential backoff: the n-th retry waits
+// BaseDelay * Multiplier^(n-1), capped at MaxDelay, and then shortened by a random share of up
+// to Jitter (0..1) so that messages failing together do not hit the provider together again.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	Jitter      float64
+
+	random func() float64
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultMaxAttempts,
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    defaultMaxDelay,
+		Multiplier:  defaultMultiplier,
+		Jitter:      defaultJitter,
+	}
+}
+
+// Backoff returns the delay before the next attempt of a message that has already failed attempts times.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempts-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		random := p.random
+		if random == nil {
+			random = rand.Float64
+		}
+		delay -= delay * math.Min(p.Jitter, 1) * random()
+	}
+
+	return time.Duration(delay)
+}
+
+// applyFailure records a failed delivery attempt on the message and moves it to the next lifecycle state:
+// FAILED for permanent errors (port.ValidationError), DEAD once MaxAttempts is reached and RETRYING
+// otherwise. A Retry-After sent by the webhook postpones the next attempt beyond the backoff delay.
+func (p RetryPolicy) applyFailure(message *entity.MessagesEntity, sendErr error, now time.Time) {
+	message.LastError = truncate(sendErr.Error(), maxLastErrorLength)
+	message.NextAttemptAt = ""
+
+	var validationErr port.ValidationError
+	switch {
+	case errors.As(sendErr, &validationErr):
+		message.Status = status.FAILED
+	case message.Attempts >= p.MaxAttempts:
+		message.Status = status.DEAD
+	default:
+		delay := p.Backoff(message.Attempts)
+		if retryAfter, ok := webhook.RetryAfter(sendErr); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+
+		message.Status = status.RETRYING
+		message.NextAttemptAt = now.Add(delay).UTC().Format(time.RFC3339)
+	}
+}
+
+// truncate cuts value to at most maxLength bytes without splitting a multi-byte character, which
+// postgres would reject as invalid UTF-8.
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	for maxLength > 0 && !utf8.RuneStart(value[maxLength]) {
+		maxLength--
+	}
+	return value[:maxLength]
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay:  10 * time.Second,
+		MaxDelay:   time.Minute,
+		Multiplier: 2,
+	}
+
+	assert.Equal(t, 10*time.Second, policy.Backoff(0))
+	assert.Equal(t, 10*time.Second, policy.Backoff(1))
+	assert.Equal(t, 20*time.Second, policy.Backoff(2))
+	assert.Equal(t, 40*time.Second, policy.Backoff(3))
+	assert.Equal(t, time.Minute, policy.Backoff(4))
+	assert.Equal(t, time.Minute, policy.Backoff(30))
+}
+
+func TestRetryPolicy_BackoffJitter(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay:  10 * time.Second,
+		MaxDelay:   time.Minute,
+		Multiplier: 2,
+		Jitter:     0.5,
+		random:     func() float64 { return 1 },
+	}
+
+	assert.Equal(t, 5*time.Second, policy.Backoff(1))
+	assert.Equal(t, 30*time.Second, policy.Backoff(10))
+
+	policy.random = nil
+	for i := 0; i < 100; i++ {
+		delay := policy.Backoff(2)
+		assert.GreaterOrEqual(t, delay, 10*time.Second)
+		assert.LessOrEqual(t, delay, 20*time.Second)
+	}
+}
+
+func TestTruncate_KeepsRunesWhole(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "abc", truncate("abcdef", 3))
+
+	// "ş" takes two bytes, cutting after its first byte would leave invalid UTF-8
+	truncated := truncate("alıcı ulaşılamıyor", 12)
+	assert.Equal(t, "alıcı ula", truncated)
+	assert.True(t, utf8.ValidString(truncated))
+}
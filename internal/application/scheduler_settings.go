@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"time"
+)
+
+const (
+	defaultBatchSize     = 2
+	defaultInterval      = 2 * time.Minute
+	defaultJobTimeout    = 30 * time.Second
+	defaultConcurrency   = 4
+	defaultPriorityAging = 5 * time.Minute
+
+	maxBatchSize     = 1000
+	minInterval      = time.Second
+	minJobTimeout    = time.Second
+	maxConcurrency   = 100
+	minPriorityAging = time.Second
+)
+
+// SchedulerSettings control the message processing job. They can be changed at runtime, the job
+// picks up new values on its next tick. With a settings store the change reaches every instance,
+// otherwise only the one that handled it.
+type SchedulerSettings struct {
+	BatchSize  int
+	Interval   time.Duration
+	JobTimeout time.Duration
+	// Concurrency bounds the parallel webhook calls of a tick.
+	Concurrency int
+	// PreserveRecipientOrder sends the messages of one phone number sequentially, in claim order.
+	PreserveRecipientOrder bool
+	// PriorityAging is how long a due message waits before it is ranked one priority higher, so bulk
+	// traffic still goes out while more urgent messages keep coming in.
+	PriorityAging time.Duration
+}
+
+func DefaultSchedulerSettings() SchedulerSettings {
+	return SchedulerSettings{
+		BatchSize:     defaultBatchSize,
+		Interval:      defaultInterval,
+		JobTimeout:    defaultJobTimeout,
+		Concurrency:   defaultConcurrency,
+		PriorityAging: defaultPriorityAging,
+	}
+}
+
+// WithSchedulerSettings sets the initial settings, check them with ValidateSchedulerSettings first.
+// They are validated again once all options are applied and invalid ones are replaced by the defaults.
+func WithSchedulerSettings(settings SchedulerSettings) Option {
+	return func(is *MessageSendService) {
+		is.settings = settings
+	}
+}
+
+// WithSettingsStore shares settings changed at runtime between instances and keeps them across
+// restarts, where they take precedence over the configured ones.
+func WithSettingsStore(store repository.SchedulerSettingsRepository) Option {
+	return func(is *MessageSendService) {
+		is.settingsStore = store
+	}
+}
+
+func (is *MessageSendService) SchedulerSettings() SchedulerSettings {
+	is.settingsMutex.RLock()
+	defer is.settingsMutex.RUnlock()
+
+	return is.settings
+}
+
+// RefreshSchedulerSettings loads the settings last stored by any instance. Without a store, or before
+// settings were ever changed, the current ones are kept.
+func (is *MessageSendService) RefreshSchedulerSettings(ctx context.Context) (SchedulerSettings, error) {
+	if is.settingsStore == nil {
+		return is.SchedulerSettings(), nil
+	}
+
+	stored, err := is.settingsStore.Get(ctx)
+	if errors.Is(err, repository.ErrSchedulerSettingsNotFound) {
+		return is.SchedulerSettings(), nil
+	}
+	if err != nil {
+		return is.SchedulerSettings(), port.DBFailureError{Msg: "failed to load scheduler settings", WrappedErr: err}
+	}
+
+	settings := SchedulerSettings{
+		BatchSize:              stored.BatchSize,
+		Interval:               stored.Interval,
+		JobTimeout:             stored.JobTimeout,
+		Concurrency:            stored.Concurrency,
+		PreserveRecipientOrder: stored.PreserveRecipientOrder,
+		PriorityAging:          stored.PriorityAging,
+	}
+	if err := is.validateSchedulerSettings(settings); err != nil {
+		log.Logger.Warn().Err(err).Msg("Ignoring invalid stored scheduler settings")
+		return is.SchedulerSettings(), nil
+	}
+
+	is.settingsMutex.Lock()
+	changed := is.settings != settings
+	is.settings = settings
+	is.settingsMutex.Unlock()
+
+	if changed {
+		log.Logger.Info().Msg("Loaded stored scheduler settings")
+	}
+	return settings, nil
+}
+
+func (is *MessageSendService) UpdateSchedulerSettings(ctx context.Context, settings SchedulerSettings) error {
+	if err := is.validateSchedulerSettings(settings); err != nil {
+		return err
+	}
+
+	if is.settingsStore != nil {
+		err := is.settingsStore.Save(ctx, &entity.SchedulerSettingsEntity{
+			BatchSize:              settings.BatchSize,
+			Interval:               settings.Interval,
+			JobTimeout:             settings.JobTimeout,
+			Concurrency:            settings.Concurrency,
+			PreserveRecipientOrder: settings.PreserveRecipientOrder,
+			PriorityAging:          settings.PriorityAging,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update scheduler settings: %w", err)
+		}
+	}
+
+	is.settingsMutex.Lock()
+	is.settings = settings
+	is.settingsMutex.Unlock()
+
+	log.Logger.Info().
+		Int("batch_size", settings.BatchSize).
+		Dur("interval", settings.Interval).
+		Dur("job_timeout", settings.JobTimeout).
+		Int("concurrency", settings.Concurrency).
+		Bool("preserve_recipient_order", settings.PreserveRecipientOrder).
+		Dur("priority_aging", settings.PriorityAging).
+		Msg("Scheduler settings updated, applying on next tick")
+
+	return nil
+}
+
+func (is *MessageSendService) validateSchedulerSettings(settings SchedulerSettings) error {
+	return ValidateSchedulerSettings(settings, is.leaseDuration)
+}
+
+// ValidateSchedulerSettings checks settings for a service whose claims are leased for leaseDuration,
+// so a bad configuration can stop startup.
+func ValidateSchedulerSettings(settings SchedulerSettings, leaseDuration time.Duration) error {
+	if settings.BatchSize < 1 || settings.BatchSize > maxBatchSize {
+		return port.ValidationError{Msg: fmt.Sprintf("batchSize must be between 1 and %d", maxBatchSize)}
+	}
+
+	if settings.Interval < minInterval {
+		return port.ValidationError{Msg: fmt.Sprintf("interval must be at least %s", minInterval)}
+	}
+
+	if settings.Concurrency < 1 || settings.Concurrency > maxConcurrency {
+		return port.ValidationError{Msg: fmt.Sprintf("concurrency must be between 1 and %d", maxConcurrency)}
+	}
+
+	if settings.PriorityAging < minPriorityAging {
+		return port.ValidationError{Msg: fmt.Sprintf("priorityAging must be at least %s", minPriorityAging)}
+	}
+
+	if settings.JobTimeout < minJobTimeout {
+		return port.ValidationError{Msg: fmt.Sprintf("jobTimeout must be at least %s", minJobTimeout)}
+	}
+
+	// a job outliving the lease of its messages lets another instance claim and send them again
+	if settings.JobTimeout >= leaseDuration {
+		return port.ValidationError{Msg: fmt.Sprintf("jobTimeout must be shorter than the lease duration %s", leaseDuration)}
+	}
+
+	return nil
+}
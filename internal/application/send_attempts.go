@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/attempt"
+	"message-scheduler/internal/infra/client/webhook"
+	"message-scheduler/internal/infra/repository"
+	"message-scheduler/log"
+)
+
+// WithSendAttempts records every webhook call before it is made. Without it sends are not recorded
+// and a crash between a send and saving the message can only be caught by the provider honouring
+// the idempotency key.
+func WithSendAttempts(sendAttempts repository.SendAttemptsRepository) Option {
+	return func(is *MessageSendService) {
+		is.sendAttempts = sendAttempts
+	}
+}
+
+// idempotencyKey identifies one attempt of a message. Attempts are only counted once the outcome is
+// saved, so an attempt repeated after a crash gets the same key.
+func idempotencyKey(message *entity.MessagesEntity) string {
+	return fmt.Sprintf("message-%s-attempt-%d", message.Id, message.Attempts)
+}
+
+// beginSendAttempt records the attempt about to be made, or returns the earlier record of the same
+// attempt when the message is sent again after a crash.
+func (is *MessageSendService) beginSendAttempt(ctx context.Context, message *entity.MessagesEntity) (*entity.SendAttemptEntity, error) {
+	sendAttempt := &entity.SendAttemptEntity{
+		MessageId:      message.Id,
+		Attempt:        message.Attempts,
+		IdempotencyKey: idempotencyKey(message),
+		Status:         attempt.PENDING,
+	}
+
+	if is.sendAttempts == nil {
+		return sendAttempt, nil
+	}
+
+	recorded, err := is.sendAttempts.Begin(ctx, sendAttempt)
+	if err != nil {
+		log.Logger.Error().Err(err).Str("message_id", message.Id).Msg("Failed to record send attempt, not sending")
+		return nil, err
+	}
+
+	return recorded, nil
+}
+
+func (is *MessageSendService) finishSendAttempt(ctx context.Context, sendAttempt *entity.SendAttemptEntity, outcome attempt.SendAttemptStatus, response *webhook.WebhookResponse, sendErr error) {
+	if is.sendAttempts == nil {
+		return
+	}
+
+	sendAttempt.Status = outcome
+	if response != nil {
+		sendAttempt.RemoteMessageId = response.MessageID
+		sendAttempt.Provider = response.Provider
+	}
+	if sendErr != nil {
+		sendAttempt.Error = sendErr.Error()
+	}
+
+	// an attempt left pending is merely sent again with the same key
+	if err := is.sendAttempts.Finish(ctx, sendAttempt); err != nil {
+		log.Logger.Error().Err(err).Str("message_id", sendAttempt.MessageId).Str("status", string(outcome)).Msg("Failed to record send attempt outcome")
+	}
+}
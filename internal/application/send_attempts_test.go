@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/attempt"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/client/webhook"
+	"message-scheduler/internal/port"
+	"message-scheduler/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSendAttemptsTestService() (*MessageSendService, *mocks.WebhookClientMock, *mocks.MessagesRepositoryMock, *mocks.SendAttemptsRepositoryMock) {
+	mockWebhook := &mocks.WebhookClientMock{}
+	mockRepo := &mocks.MessagesRepositoryMock{}
+	mockAttempts := &mocks.SendAttemptsRepositoryMock{}
+
+	service := NewMessageSendService(mockWebhook, mockRepo, &mocks.SchedulerMock{}, WithSendAttempts(mockAttempts))
+	return service, mockWebhook, mockRepo, mockAttempts
+}
+
+func TestIdempotencyKey_StableAcrossRepeatedAttempt(t *testing.T) {
+	message := &entity.MessagesEntity{Id: "42", Attempts: 2}
+
+	assert.Equal(t, "message-42-attempt-2", idempotencyKey(message))
+	assert.Equal(t, idempotencyKey(message), idempotencyKey(&entity.MessagesEntity{Id: "42", Attempts: 2}))
+	assert.NotEqual(t, idempotencyKey(message), idempotencyKey(&entity.MessagesEntity{Id: "42", Attempts: 3}))
+}
+
+func TestSendMessage_RecordsAttemptBeforeSending(t *testing.T) {
+	service, mockWebhook, mockRepo, mockAttempts := newSendAttemptsTestService()
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+	key := fmt.Sprintf("message-%s-attempt-1", message.Id)
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockAttempts.On("Begin", ctx, mock.MatchedBy(func(a *entity.SendAttemptEntity) bool {
+		return a.MessageId == message.Id && a.Attempt == 1 && a.IdempotencyKey == key && a.Status == attempt.PENDING
+	})).Return(&entity.SendAttemptEntity{Id: "7", MessageId: message.Id, Attempt: 1, IdempotencyKey: key, Status: attempt.PENDING}, nil).Once()
+	mockWebhook.On("SendMessage", ctx, message, key).Return(&webhook.WebhookResponse{MessageID: "webhook-msg-123", Provider: "backup"}, nil).Once()
+	mockAttempts.On("Finish", ctx, mock.MatchedBy(func(a *entity.SendAttemptEntity) bool {
+		return a.IdempotencyKey == key && a.Status == attempt.SUCCEEDED && a.RemoteMessageId == "webhook-msg-123" && a.Provider == "backup"
+	})).Return(nil).Once()
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.SENT && msg.RemoteMessageId == "webhook-msg-123" && msg.Provider == "backup"
+	}), mock.Anything).Return(true, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Succeeded: 1}, result)
+	mockAttempts.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendMessage_ReconcilesSucceededAttemptWithoutSending(t *testing.T) {
+	service, mockWebhook, mockRepo, mockAttempts := newSendAttemptsTestService()
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockAttempts.On("Begin", ctx, mock.Anything).Return(&entity.SendAttemptEntity{
+		MessageId:       message.Id,
+		Attempt:         1,
+		IdempotencyKey:  idempotencyKey(&entity.MessagesEntity{Id: message.Id, Attempts: 1}),
+		Status:          attempt.SUCCEEDED,
+		RemoteMessageId: "webhook-msg-earlier",
+		Provider:        "default",
+	}, nil)
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.SENT && msg.RemoteMessageId == "webhook-msg-earlier" && msg.Provider == "default" && msg.Attempts == 1
+	}), mock.Anything).Return(true, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Succeeded: 1}, result)
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendMessage_RecordsFailedAttempt(t *testing.T) {
+	service, mockWebhook, mockRepo, mockAttempts := newSendAttemptsTestService()
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+	pending := &entity.SendAttemptEntity{MessageId: message.Id, Attempt: 1, IdempotencyKey: "message-x-attempt-1", Status: attempt.PENDING}
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockAttempts.On("Begin", ctx, mock.Anything).Return(pending, nil)
+	mockWebhook.On("SendMessage", ctx, message, "message-x-attempt-1").Return(nil, port.ValidationError{Msg: "invalid recipient"})
+	mockAttempts.On("Finish", ctx, mock.MatchedBy(func(a *entity.SendAttemptEntity) bool {
+		return a.Status == attempt.FAILED && a.Error == "invalid recipient"
+	})).Return(nil).Once()
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.FAILED
+	}), mock.Anything).Return(true, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Failed: 1}, result)
+	mockAttempts.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendMessage_DoesNotSendWhenAttemptCannotBeRecorded(t *testing.T) {
+	service, mockWebhook, mockRepo, mockAttempts := newSendAttemptsTestService()
+
+	ctx := context.Background()
+	message := createTestMessage(status.UNSENT)
+
+	mockRepo.On("ClaimMessages", ctx, claimLimit(1)).Return([]*entity.MessagesEntity{message}, nil)
+	mockAttempts.On("Begin", ctx, mock.Anything).Return(nil, fmt.Errorf("database error"))
+	mockRepo.On("SaveClaimed", ctx, mock.MatchedBy(func(msg *entity.MessagesEntity) bool {
+		return msg.Status == status.UNSENT && msg.Attempts == 0
+	}), mock.Anything).Return(true, nil).Once()
+
+	result, err := service.ProcessUnsentMessages(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProcessResult{Skipped: 1}, result)
+	mockWebhook.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
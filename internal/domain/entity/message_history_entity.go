@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/domain/types/status"
+)
+
+// MessageHistoryEntity is a snapshot of a message taken whenever its status, content, priority or
+// send time changed.
+type MessageHistoryEntity struct {
+	Id              string
+	MessageId       string
+	Status          status.MessageStatus
+	Priority        priority.MessagePriority
+	Content         string
+	SendAt          string
+	Attempts        int
+	LastError       string
+	RemoteMessageId string
+	Version         int
+	RecordedAt      string
+}
@@ -1,14 +1,39 @@
 package entity
 
-import "message-scheduler/internal/domain/types/status"
+import (
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/domain/types/status"
+)
 
 type MessagesEntity struct {
 	Id              string
 	Phone           string
 	Content         string
 	Status          status.MessageStatus
+	Priority        priority.MessagePriority
+	Tags            []string
 	CreatedAt       string
 	UpdatedAt       string
+	SendAt          string
 	SentAt          string
 	RemoteMessageId string
+	Provider        string // name of the webhook provider that accepted the message
+	Attempts        int
+	LastError       string
+	NextAttemptAt   string
+	LeaseOwner      string
+	LeaseExpiresAt  string
+	// DeliveryReportedAt is the provider's timestamp of the latest delivery receipt, DeliveryError
+	// its reason when the message could not be delivered.
+	DeliveryReportedAt string
+	DeliveryError      string
+	// Version is bumped whenever the message is edited or claimed, for optimistic concurrency.
+	Version int
+	// TemplateId and Variables are set on messages enqueued from a template, whose content is rendered
+	// again right before it is sent; TemplateVersion is the version it was last rendered with. Locale
+	// is the recipient's language tag, e.g. tr-TR, and picks the variant of the template.
+	TemplateId      string
+	TemplateVersion int
+	Variables       map[string]string
+	Locale          string
 }
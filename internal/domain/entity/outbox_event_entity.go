@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"encoding/json"
+	"message-scheduler/internal/domain/types/event"
+)
+
+// OutboxEventEntity is a status transition waiting to be published. The database writes it in the
+// same transaction as the status change, so no transition is lost or published without being saved.
+type OutboxEventEntity struct {
+	Id        string
+	Type      event.EventType
+	MessageId string
+	// Payload is a JSON snapshot of the message taken with the transition.
+	Payload   json.RawMessage
+	CreatedAt string
+	Attempts  int
+	LastError string
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// SchedulerSettingsEntity holds the scheduler settings changed through the API, shared by all
+// instances so that the elected leader applies them whichever instance handled the change.
+type SchedulerSettingsEntity struct {
+	BatchSize              int
+	Interval               time.Duration
+	JobTimeout             time.Duration
+	Concurrency            int
+	PreserveRecipientOrder bool
+	PriorityAging          time.Duration
+	UpdatedAt              string
+}
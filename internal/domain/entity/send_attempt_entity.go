@@ -0,0 +1,18 @@
+package entity
+
+import "message-scheduler/internal/domain/types/attempt"
+
+// SendAttemptEntity records one webhook call for a message. It is written before the call, so a
+// crash between the call and saving the message leaves a trace to reconcile from.
+type SendAttemptEntity struct {
+	Id              string
+	MessageId       string
+	Attempt         int
+	IdempotencyKey  string
+	Status          attempt.SendAttemptStatus
+	RemoteMessageId string
+	Provider        string
+	Error           string
+	StartedAt       string
+	FinishedAt      string
+}
@@ -0,0 +1,17 @@
+package entity
+
+// TemplateEntity is one version of a message template. Content holds {{variable}} placeholders and
+// is written in Locale, if set; Variants hold the same text in other locales, keyed by language tag.
+// Variables lists the placeholder names of all variants in order of first appearance. Changing a
+// template adds a version, earlier versions are kept.
+type TemplateEntity struct {
+	Id        string
+	Name      string
+	Version   int
+	Content   string
+	Locale    string
+	Variants  map[string]string
+	Variables []string
+	CreatedAt string
+	UpdatedAt string
+}
@@ -0,0 +1,9 @@
+package attempt
+
+type SendAttemptStatus string
+
+const (
+	PENDING   SendAttemptStatus = "pending"   // recorded before the webhook call, outcome unknown until it returns
+	SUCCEEDED SendAttemptStatus = "succeeded" // the webhook accepted the message
+	FAILED    SendAttemptStatus = "failed"    // the webhook call failed, see the error
+)
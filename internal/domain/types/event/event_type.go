@@ -0,0 +1,9 @@
+package event
+
+type EventType string
+
+const (
+	MESSAGE_SENT      EventType = "message.sent"      // the webhook accepted the message
+	MESSAGE_FAILED    EventType = "message.failed"    // rejected, dead-lettered or reported undelivered, see the status
+	MESSAGE_DELIVERED EventType = "message.delivered" // the provider confirmed delivery through a receipt
+)
@@ -0,0 +1,19 @@
+package priority
+
+type MessagePriority string
+
+const (
+	CRITICAL MessagePriority = "critical" // e.g. one-time passwords, sent before anything else
+	HIGH     MessagePriority = "high"
+	NORMAL   MessagePriority = "normal" // default for messages created without a priority
+	BULK     MessagePriority = "bulk"   // e.g. marketing blasts, sent when nothing more urgent is due
+)
+
+func (p MessagePriority) Valid() bool {
+	switch p {
+	case CRITICAL, HIGH, NORMAL, BULK:
+		return true
+	default:
+		return false
+	}
+}
@@ -3,6 +3,23 @@ package status
 type MessageStatus string
 
 const (
-	UNSENT MessageStatus = "unsent"
-	SENT   MessageStatus = "sent"
+	UNSENT    MessageStatus = "unsent"
+	SENT      MessageStatus = "sent"
+	SENDING   MessageStatus = "sending"   // claimed by a scheduler instance until lease_expires_at
+	FAILED    MessageStatus = "failed"    // permanently rejected, never retried
+	RETRYING  MessageStatus = "retrying"  // failed attempt, eligible again at next_attempt_at
+	DEAD      MessageStatus = "dead"      // retries exhausted, moved to dead-letter
+	CANCELLED MessageStatus = "cancelled" // withdrawn through the API before it was sent
+	// DELIVERED and UNDELIVERED follow SENT once the provider reports the outcome through a delivery receipt
+	DELIVERED   MessageStatus = "delivered"
+	UNDELIVERED MessageStatus = "undelivered"
 )
+
+func (s MessageStatus) Valid() bool {
+	switch s {
+	case UNSENT, SENT, SENDING, FAILED, RETRYING, DEAD, CANCELLED, DELIVERED, UNDELIVERED:
+		return true
+	default:
+		return false
+	}
+}
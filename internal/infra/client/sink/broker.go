@@ -0,0 +1,40 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+)
+
+// Producer is implemented by adapters for message brokers such as Kafka, NATS or RabbitMQ. Produce
+// returns once the broker has acknowledged the record.
+type Producer interface {
+	Produce(ctx context.Context, topic string, key string, value []byte) error
+}
+
+// BrokerSink publishes every event as one record to topic. Records are keyed by message id, so a
+// partitioned broker keeps the events of a message in order.
+type BrokerSink struct {
+	producer Producer
+	topic    string
+}
+
+func NewBrokerSink(producer Producer, topic string) *BrokerSink {
+	return &BrokerSink{producer: producer, topic: topic}
+}
+
+func (s *BrokerSink) Publish(ctx context.Context, events []*entity.OutboxEventEntity) error {
+	for _, event := range newEvents(events) {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		if err := s.producer.Produce(ctx, s.topic, event.MessageID, value); err != nil {
+			return fmt.Errorf("failed to produce event %s: %w", event.ID, err)
+		}
+	}
+
+	return nil
+}
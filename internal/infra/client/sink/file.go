@@ -0,0 +1,47 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"os"
+	"sync"
+)
+
+// FileSink appends events as JSON lines to a file. The file is reopened for every batch, so it can
+// be rotated by moving it away.
+type FileSink struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Publish(_ context.Context, events []*entity.OutboxEventEntity) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open event file: %w", err)
+	}
+
+	encoder := json.NewEncoder(file)
+	for _, event := range newEvents(events) {
+		if err := encoder.Encode(event); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("failed to write event %s: %w", event.ID, err)
+		}
+	}
+
+	// the batch only counts as published once it is on disk
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to sync event file: %w", err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,60 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/signature"
+	"net/http"
+	"time"
+)
+
+// HTTPSink posts every batch as a JSON array of events to a single endpoint. Any 2xx answer
+// acknowledges the whole batch.
+type HTTPSink struct {
+	client *http.Client
+	url    string
+	signer *signature.Signer
+}
+
+// NewHTTPSink creates a sink posting to url. With a signer every request carries the
+// X-Webhook-Signature header, so receivers can verify it like a webhook request.
+func NewHTTPSink(url string, timeout time.Duration, signer *signature.Signer) *HTTPSink {
+	return &HTTPSink{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+		signer: signer,
+	}
+}
+
+func (s *HTTPSink) Publish(ctx context.Context, events []*entity.OutboxEventEntity) error {
+	body, err := json.Marshal(newEvents(events))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.signer != nil {
+		req.Header.Set(signature.Header, s.signer.Sign(body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("event sink request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode <= 299) {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("event sink rejected events, status_code=%d, body=%s", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"message-scheduler/internal/domain/entity"
+)
+
+// EventSink publishes outbox events to downstream systems. Publish either accepts the whole batch
+// or returns an error, in which case the batch is published again later; consumers may therefore
+// see an event more than once and should deduplicate by its id.
+type EventSink interface {
+	Publish(ctx context.Context, events []*entity.OutboxEventEntity) error
+}
+
+// Event is the published form of an outbox event, shared by all sinks.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	MessageID  string          `json:"messageId"`
+	OccurredAt string          `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func NewEvent(e *entity.OutboxEventEntity) Event {
+	return Event{
+		ID:         e.Id,
+		Type:       string(e.Type),
+		MessageID:  e.MessageId,
+		OccurredAt: e.CreatedAt,
+		Data:       e.Payload,
+	}
+}
+
+func newEvents(events []*entity.OutboxEventEntity) []Event {
+	published := make([]Event, len(events))
+	for i, e := range events {
+		published[i] = NewEvent(e)
+	}
+	return published
+}
@@ -0,0 +1,108 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/event"
+	"message-scheduler/signature"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEvents = []*entity.OutboxEventEntity{
+	{Id: "1", Type: event.MESSAGE_SENT, MessageId: "42", CreatedAt: "2025-01-02T06:00:00Z", Payload: json.RawMessage(`{"status":"sent"}`)},
+	{Id: "2", Type: event.MESSAGE_FAILED, MessageId: "43", CreatedAt: "2025-01-02T06:00:01Z", Payload: json.RawMessage(`{"status":"dead"}`)},
+}
+
+func TestHTTPSink_PostsSignedBatch(t *testing.T) {
+	signer, err := signature.NewSigner("secret")
+	assert.NoError(t, err)
+	verifier, err := signature.NewVerifier(time.Minute, "secret")
+	assert.NoError(t, err)
+
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := verifier.VerifyRequest(r)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	err = NewHTTPSink(server.URL, time.Second, signer).Publish(context.Background(), testEvents)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{
+		{ID: "1", Type: "message.sent", MessageID: "42", OccurredAt: "2025-01-02T06:00:00Z", Data: json.RawMessage(`{"status":"sent"}`)},
+		{ID: "2", Type: "message.failed", MessageID: "43", OccurredAt: "2025-01-02T06:00:01Z", Data: json.RawMessage(`{"status":"dead"}`)},
+	}, received)
+}
+
+func TestHTTPSink_RejectedBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	err := NewHTTPSink(server.URL, time.Second, nil).Publish(context.Background(), testEvents)
+
+	assert.ErrorContains(t, err, "status_code=503")
+}
+
+func TestFileSink_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	fileSink := NewFileSink(path)
+
+	assert.NoError(t, fileSink.Publish(context.Background(), testEvents[:1]))
+	assert.NoError(t, fileSink.Publish(context.Background(), testEvents[1:]))
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e Event
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"1", "2"}, ids)
+}
+
+type recordingProducer struct {
+	keys []string
+	fail bool
+}
+
+func (p *recordingProducer) Produce(_ context.Context, topic string, key string, _ []byte) error {
+	if p.fail {
+		return fmt.Errorf("broker unavailable")
+	}
+	p.keys = append(p.keys, topic+"/"+key)
+	return nil
+}
+
+func TestBrokerSink_KeysRecordsByMessage(t *testing.T) {
+	producer := &recordingProducer{}
+
+	err := NewBrokerSink(producer, "message-events").Publish(context.Background(), testEvents)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"message-events/42", "message-events/43"}, producer.keys)
+
+	err = NewBrokerSink(&recordingProducer{fail: true}, "message-events").Publish(context.Background(), testEvents)
+	assert.ErrorContains(t, err, "failed to produce event 1")
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+)
+
+// defaultAPIKeyHeader carries the key when the configuration does not name a header.
+const defaultAPIKeyHeader = "X-API-Key"
+
+// Authenticator adds credentials to every webhook request. An error aborts the request; it should be
+// a port.DependencyError when the credentials may be obtainable later.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// tokenInvalidator is implemented by authenticators caching a token the provider may revoke early.
+// The client drops the token when the provider answers 401, so the next attempt fetches a new one.
+type tokenInvalidator interface {
+	Invalidate()
+}
+
+type bearerAuth struct {
+	token string
+}
+
+// BearerToken sends a static token in the Authorization header.
+func BearerToken(token string) Authenticator {
+	return bearerAuth{token: token}
+}
+
+func (a bearerAuth) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+// BasicAuth sends HTTP basic credentials.
+func BasicAuth(username string, password string) Authenticator {
+	return basicAuth{username: username, password: password}
+}
+
+func (a basicAuth) Authenticate(_ context.Context, req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+type apiKeyAuth struct {
+	header string
+	key    string
+}
+
+// APIKey sends the key in the given header, X-API-Key when header is empty.
+func APIKey(header string, key string) Authenticator {
+	if header == "" {
+		header = defaultAPIKeyHeader
+	}
+	return apiKeyAuth{header: header, key: key}
+}
+
+func (a apiKeyAuth) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set(a.header, a.key)
+	return nil
+}
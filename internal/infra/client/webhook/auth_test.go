@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"message-scheduler/internal/port"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessage_StaticCredentials(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   Authenticator
+		header string
+		want   string
+	}{
+		{name: "bearer", auth: BearerToken("token-1"), header: "Authorization", want: "Bearer token-1"},
+		{name: "basic", auth: BasicAuth("user", "pass"), header: "Authorization", want: "Basic dXNlcjpwYXNz"},
+		{name: "api key", auth: APIKey("", "key-1"), header: "X-API-Key", want: "key-1"},
+		{name: "api key custom header", auth: APIKey("X-Token", "key-1"), header: "X-Token", want: "key-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header.Get(tt.header)
+				_, _ = w.Write([]byte(`{"messageId":"remote-1"}`))
+			}))
+			t.Cleanup(server.Close)
+
+			client := NewWebhookClient(server.URL, time.Second, WithAuthenticator(tt.auth))
+			_, err := client.SendMessage(context.Background(), testMessage, "")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, received)
+		})
+	}
+}
+
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, _ := r.BasicAuth()
+		if clientID != "client" || secret != "secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "messages.send audit", r.FormValue("scope"))
+
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &issued
+}
+
+func TestOAuth2ClientCredentials_CachesUntilExpiry(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	auth := NewOAuth2ClientCredentials(OAuth2Settings{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"messages.send", "audit"},
+	}, time.Second)
+	auth.now = func() time.Time { return now }
+
+	authorize := func() string {
+		req, _ := http.NewRequest(http.MethodPost, "http://webhook", nil)
+		assert.NoError(t, auth.Authenticate(context.Background(), req))
+		return req.Header.Get("Authorization")
+	}
+
+	assert.Equal(t, "Bearer token-1", authorize())
+	assert.Equal(t, "Bearer token-1", authorize())
+	assert.Equal(t, int32(1), issued.Load())
+
+	// renewed shortly before it expires
+	now = now.Add(time.Hour - tokenExpiryMargin)
+	assert.Equal(t, "Bearer token-2", authorize())
+
+	auth.Invalidate()
+	assert.Equal(t, "Bearer token-3", authorize())
+}
+
+func TestOAuth2ClientCredentials_RejectedTokenIsRetried(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+	var calls atomic.Int32
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "Bearer token-2", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"messageId":"remote-1"}`))
+	}))
+	t.Cleanup(webhookServer.Close)
+
+	auth := NewOAuth2ClientCredentials(OAuth2Settings{TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "secret", Scopes: []string{"messages.send", "audit"}}, time.Second)
+	client := NewWebhookClient(webhookServer.URL, time.Second, WithAuthenticator(auth))
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+	assert.ErrorAs(t, err, &port.DependencyError{})
+
+	_, err = client.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestOAuth2ClientCredentials_TokenFailureIsRetryable(t *testing.T) {
+	tokenServer, _ := newTokenServer(t, 3600)
+	auth := NewOAuth2ClientCredentials(OAuth2Settings{TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "wrong"}, time.Second)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://webhook", nil)
+	err := auth.Authenticate(context.Background(), req)
+
+	var webhookErr *Error
+	assert.ErrorAs(t, err, &port.DependencyError{})
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, http.StatusUnauthorized, webhookErr.StatusCode)
+}
+
+func TestSendMessage_MutualTLS(t *testing.T) {
+	clientCert := newSelfSignedCertificate(t)
+	clientPool := x509.NewCertPool()
+	clientPool.AddCert(clientCert.Leaf)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"messageId":"remote-1"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientPool}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	serverPool := x509.NewCertPool()
+	serverPool.AddCert(server.Certificate())
+
+	_, err := NewWebhookClient(server.URL, time.Second, WithRootCAs(serverPool)).
+		SendMessage(context.Background(), testMessage, "")
+	assert.Error(t, err, "server requires a client certificate")
+
+	_, err = NewWebhookClient(server.URL, time.Second, WithRootCAs(serverPool), WithClientCertificate(clientCert)).
+		SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+}
+
+func newSelfSignedCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "message-scheduler"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"math"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type BreakerState string
+
+const (
+	// BreakerClosed lets every call through and counts consecutive failures.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects every call until the cool-down has passed.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe through; its outcome closes or reopens the breaker.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// ErrCircuitOpen is returned without calling the webhook while the breaker is open. Like
+// ErrRateLimited it means the message was not sent.
+var ErrCircuitOpen = errors.New("webhook circuit breaker is open, message not sent")
+
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// CoolDown is how long the breaker stays open before it lets a probe through.
+	CoolDown time.Duration
+}
+
+type BreakerStatus struct {
+	State               BreakerState
+	ConsecutiveFailures int
+	OpenedAt            time.Time
+}
+
+// BreakerAware is implemented by clients guarded by a circuit breaker.
+type BreakerAware interface {
+	BreakerStatus() BreakerStatus
+}
+
+// CircuitBreakerClient stops calling an unhealthy webhook. Only failures that say the provider is
+// down count, i.e. network errors and 5xx; rejected messages and throttling do not.
+type CircuitBreakerClient struct {
+	next     WebhookClient
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mutex           sync.Mutex
+	state           BreakerState
+	failures        int
+	openedAt        time.Time
+	probeInProgress bool
+}
+
+func NewCircuitBreakerClient(next WebhookClient, settings CircuitBreakerSettings) *CircuitBreakerClient {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 1
+	}
+
+	return &CircuitBreakerClient{
+		next:     next,
+		settings: settings,
+		now:      time.Now,
+		state:    BreakerClosed,
+	}
+}
+
+func (c *CircuitBreakerClient) SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*WebhookResponse, error) {
+	probe, err := c.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.next.SendMessage(ctx, message, idempotencyKey)
+	c.record(probe, err)
+
+	return response, err
+}
+
+// Available returns 0 while calls would be rejected and otherwise defers to the wrapped client.
+func (c *CircuitBreakerClient) Available(ctx context.Context) int {
+	available := math.MaxInt
+	if quota, ok := c.next.(QuotaAware); ok {
+		available = quota.Available(ctx)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch c.currentState() {
+	case BreakerOpen:
+		return 0
+	case BreakerHalfOpen:
+		if c.probeInProgress {
+			return 0
+		}
+		return min(available, 1)
+	default:
+		return available
+	}
+}
+
+func (c *CircuitBreakerClient) BreakerStatus() BreakerStatus {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return BreakerStatus{
+		State:               c.currentState(),
+		ConsecutiveFailures: c.failures,
+		OpenedAt:            c.openedAt,
+	}
+}
+
+// currentState reports an open breaker whose cool-down has passed as half-open.
+func (c *CircuitBreakerClient) currentState() BreakerState {
+	if c.state == BreakerOpen && !c.now().Before(c.openedAt.Add(c.settings.CoolDown)) {
+		return BreakerHalfOpen
+	}
+	return c.state
+}
+
+func (c *CircuitBreakerClient) allow() (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch c.currentState() {
+	case BreakerOpen:
+		return false, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if c.probeInProgress {
+			return false, ErrCircuitOpen
+		}
+		c.state = BreakerHalfOpen
+		c.probeInProgress = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (c *CircuitBreakerClient) record(probe bool, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if probe {
+		c.probeInProgress = false
+	}
+
+	// a call that never reached the provider tells nothing about its health
+	if errors.Is(err, ErrRateLimited) {
+		return
+	}
+
+	if !providerFailure(err) {
+		if c.state != BreakerClosed {
+			log.Logger.Info().Msg("Webhook circuit breaker closed")
+		}
+		c.state = BreakerClosed
+		c.failures = 0
+		c.openedAt = time.Time{}
+		return
+	}
+
+	c.failures++
+	if probe || c.failures >= c.settings.FailureThreshold {
+		if c.state != BreakerOpen {
+			log.Logger.Warn().Int("consecutive_failures", c.failures).Dur("cool_down", c.settings.CoolDown).Msg("Webhook circuit breaker opened")
+		}
+		c.state = BreakerOpen
+		c.openedAt = c.now()
+	}
+}
+
+func providerFailure(err error) bool {
+	var dependencyErr port.DependencyError
+	if !errors.As(err, &dependencyErr) {
+		return false
+	}
+
+	var webhookErr *Error
+	return !(errors.As(err, &webhookErr) && webhookErr.StatusCode == http.StatusTooManyRequests)
+}
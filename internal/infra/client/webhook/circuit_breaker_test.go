@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"math"
+	"message-scheduler/internal/port"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCircuitBreakerClient(next WebhookClient, now *time.Time) *CircuitBreakerClient {
+	client := NewCircuitBreakerClient(next, CircuitBreakerSettings{FailureThreshold: 2, CoolDown: time.Minute})
+	client.now = func() time.Time { return *now }
+	return client
+}
+
+func unavailableError() error {
+	return port.DependencyError{Msg: "webhook unavailable", WrappedErr: &Error{Cause: ProtocolCause, StatusCode: http.StatusServiceUnavailable}}
+}
+
+func TestCircuitBreakerClient_OpensAfterConsecutiveFailures(t *testing.T) {
+	next := &fakeWebhookClient{err: unavailableError()}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := newTestCircuitBreakerClient(next, &now)
+
+	_, _ = client.SendMessage(context.Background(), testMessage, "")
+	assert.Equal(t, BreakerClosed, client.BreakerStatus().State)
+
+	_, _ = client.SendMessage(context.Background(), testMessage, "")
+	assert.Equal(t, BreakerStatus{State: BreakerOpen, ConsecutiveFailures: 2, OpenedAt: now}, client.BreakerStatus())
+	assert.Equal(t, 0, client.Available(context.Background()))
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestCircuitBreakerClient_SuccessResetsFailures(t *testing.T) {
+	next := &fakeWebhookClient{err: unavailableError()}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := newTestCircuitBreakerClient(next, &now)
+
+	_, _ = client.SendMessage(context.Background(), testMessage, "")
+	next.err = nil
+	_, _ = client.SendMessage(context.Background(), testMessage, "")
+	next.err = unavailableError()
+	_, _ = client.SendMessage(context.Background(), testMessage, "")
+
+	assert.Equal(t, BreakerStatus{State: BreakerClosed, ConsecutiveFailures: 1}, client.BreakerStatus())
+}
+
+func TestCircuitBreakerClient_IgnoresRejectionsAndThrottling(t *testing.T) {
+	testCases := []error{
+		port.ValidationError{Msg: "webhook rejected message", WrappedErr: &Error{Cause: ProtocolCause, StatusCode: http.StatusBadRequest}},
+		port.DependencyError{Msg: "webhook unavailable", WrappedErr: &Error{Cause: ProtocolCause, StatusCode: http.StatusTooManyRequests}},
+		ErrRateLimited,
+	}
+
+	for _, sendErr := range testCases {
+		next := &fakeWebhookClient{err: sendErr}
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		client := newTestCircuitBreakerClient(next, &now)
+
+		for i := 0; i < 3; i++ {
+			_, _ = client.SendMessage(context.Background(), testMessage, "")
+		}
+
+		assert.Equal(t, BreakerClosed, client.BreakerStatus().State, sendErr.Error())
+		assert.Equal(t, 3, next.calls)
+	}
+}
+
+func TestCircuitBreakerClient_HalfOpenProbe(t *testing.T) {
+	next := &fakeWebhookClient{err: unavailableError()}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := newTestCircuitBreakerClient(next, &now)
+
+	for i := 0; i < 2; i++ {
+		_, _ = client.SendMessage(context.Background(), testMessage, "")
+	}
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, BreakerHalfOpen, client.BreakerStatus().State)
+	assert.Equal(t, 1, client.Available(context.Background()))
+
+	// a failed probe reopens the breaker for another cool-down
+	_, _ = client.SendMessage(context.Background(), testMessage, "")
+	assert.Equal(t, BreakerStatus{State: BreakerOpen, ConsecutiveFailures: 3, OpenedAt: now}, client.BreakerStatus())
+	assert.Equal(t, 3, next.calls)
+
+	now = now.Add(time.Minute)
+	next.err = nil
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerStatus{State: BreakerClosed}, client.BreakerStatus())
+	assert.Equal(t, math.MaxInt, client.Available(context.Background()))
+}
+
+func TestCircuitBreakerClient_AvailableDefersToWrappedQuota(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limited := newTestRateLimitedClient(&fakeWebhookClient{}, RateLimit{DailyCap: 5}, &now)
+	client := newTestCircuitBreakerClient(limited, &now)
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, client.Available(context.Background()))
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Cause string
+
+const (
+	// NetworkCause means no HTTP response was received: dial failures, resets and timeouts.
+	NetworkCause Cause = "network"
+	// ProtocolCause means the webhook answered, but with an error status or an unreadable body.
+	ProtocolCause Cause = "protocol"
+)
+
+// Error describes a failed webhook call. The client wraps it in port.DependencyError when the
+// call may succeed later and in port.ValidationError when the webhook rejected the message for good.
+type Error struct {
+	Cause      Cause
+	StatusCode int
+	Body       string
+	Timeout    bool
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Cause == NetworkCause {
+		return fmt.Sprintf("webhook request failed, timeout=%t: %v", e.Timeout, e.Err)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("status_code=%d, %v", e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("status_code=%d, body=%s", e.StatusCode, e.Body)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed later: network failures, 401, 403, 408, 429
+// and 5xx. Rejected credentials are wrong for every message alike, so messages wait until they are
+// fixed instead of failing.
+func (e *Error) Retryable() bool {
+	if e.Cause == NetworkCause {
+		return true
+	}
+	return e.StatusCode == http.StatusUnauthorized ||
+		e.StatusCode == http.StatusForbidden ||
+		e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= http.StatusInternalServerError
+}
+
+// NotSent reports whether the call failed before the request could reach the provider: the host
+// was not found or the connection was not established. Only then is the message known not to have
+// gone out; after a timeout or an error status the provider may have sent it.
+func NotSent(err error) bool {
+	var webhookErr *Error
+	if !errors.As(err, &webhookErr) || webhookErr.Cause != NetworkCause {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(webhookErr.Err, &dnsErr) || (errors.As(webhookErr.Err, &opErr) && opErr.Op == "dial")
+}
+
+// RetryAfter returns the delay requested by the webhook through the Retry-After header, if any.
+func RetryAfter(err error) (time.Duration, bool) {
+	var webhookErr *Error
+	if errors.As(err, &webhookErr) && webhookErr.RetryAfter > 0 {
+		return webhookErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+func newNetworkError(err error) *Error {
+	var netErr net.Error
+	timeout := errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+
+	return &Error{Cause: NetworkCause, Timeout: timeout, Err: err}
+}
+
+func newStatusError(resp *http.Response, body []byte) *Error {
+	return &Error{
+		Cause:      ProtocolCause,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter accepts both forms allowed by RFC 9110: delay-seconds and an HTTP-date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
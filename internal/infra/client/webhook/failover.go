@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+)
+
+// NamedClient is a provider's client together with the name it is configured under.
+type NamedClient struct {
+	Name   string
+	Client WebhookClient
+}
+
+// FailoverClient tries its providers in order and moves on to the next one when a provider is known
+// not to have sent the message: it is rate limited, its circuit breaker is open or it could not be
+// connected to. After a timeout or an error status the provider may have sent the message, so the
+// failure is returned and the message retried on the same provider until its breaker opens; a
+// rejected message is not offered to the other providers either. Every message starts with the first
+// provider again, so traffic returns to it as soon as it recovers; with a circuit breaker in front
+// of it an unhealthy primary costs no time until the breaker lets a probe through.
+type FailoverClient struct {
+	providers []NamedClient
+}
+
+func NewFailoverClient(primary NamedClient, fallbacks ...NamedClient) *FailoverClient {
+	return &FailoverClient{providers: append([]NamedClient{primary}, fallbacks...)}
+}
+
+func (c *FailoverClient) SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*WebhookResponse, error) {
+	// sendErr is the failure of a provider that was actually called. It is reported in preference
+	// to rate limits and open breakers, which would let the caller skip charging an attempt.
+	var sendErr, skipErr error
+
+	for i, provider := range c.providers {
+		response, err := provider.Client.SendMessage(ctx, message, idempotencyKey)
+		if err == nil {
+			if i > 0 {
+				log.Logger.Warn().Str("message_id", message.Id).Str("provider", provider.Name).Msg("Message sent through failover provider")
+			}
+			return response, nil
+		}
+
+		switch {
+		case errors.Is(err, ErrRateLimited) || errors.Is(err, ErrCircuitOpen):
+			skipErr = err
+		case errors.As(err, &port.DependencyError{}) && NotSent(err):
+			sendErr = err
+		default:
+			return nil, err
+		}
+
+		if ctx.Err() != nil || i == len(c.providers)-1 {
+			break
+		}
+		log.Logger.Warn().Err(err).Str("message_id", message.Id).Str("provider", provider.Name).Msg("Provider unavailable, trying next provider")
+	}
+
+	if sendErr != nil {
+		return nil, sendErr
+	}
+	return nil, skipErr
+}
+
+// Available adds up the quotas of the providers, a message can go out as long as one of them accepts it.
+func (c *FailoverClient) Available(ctx context.Context) int {
+	clients := make([]WebhookClient, len(c.providers))
+	for i, provider := range c.providers {
+		clients[i] = provider.Client
+	}
+	return totalAvailable(ctx, clients)
+}
+
+func (c *FailoverClient) BreakerStatuses() map[string]BreakerStatus {
+	statuses := make(map[string]BreakerStatus)
+	for _, provider := range c.providers {
+		if breakerAware, ok := provider.Client.(BreakerAware); ok {
+			statuses[provider.Name] = breakerAware.BreakerStatus()
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"message-scheduler/internal/port"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func refusedError() error {
+	return port.DependencyError{
+		Msg:        "webhook request failed",
+		WrappedErr: newNetworkError(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}),
+	}
+}
+
+func TestFailoverClient_UsesNextProviderWhenPrimaryUnreachable(t *testing.T) {
+	// a closed server refuses connections, so the message never reached the primary
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	primaryServer.Close()
+	backupServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "message-42-attempt-1", r.Header.Get(IdempotencyHeader))
+		_, _ = w.Write([]byte(`{"messageId":"backup-1"}`))
+	}))
+	t.Cleanup(backupServer.Close)
+
+	client := NewFailoverClient(
+		NamedClient{Name: "primary", Client: NewWebhookClient(primaryServer.URL, time.Second, WithProviderName("primary"))},
+		NamedClient{Name: "backup", Client: NewWebhookClient(backupServer.URL, time.Second, WithProviderName("backup"))},
+	)
+
+	response, err := client.SendMessage(context.Background(), testMessage, "message-42-attempt-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &WebhookResponse{MessageID: "backup-1", Provider: "backup"}, response)
+}
+
+func TestFailoverClient_AmbiguousFailureIsNotFailedOver(t *testing.T) {
+	timedOut := port.DependencyError{Msg: "webhook request failed", WrappedErr: newNetworkError(context.DeadlineExceeded)}
+
+	for _, failure := range []error{unavailableError(), timedOut} {
+		primary := &fakeWebhookClient{err: failure}
+		backup := &fakeWebhookClient{}
+		client := NewFailoverClient(NamedClient{Name: "primary", Client: primary}, NamedClient{Name: "backup", Client: backup})
+
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+
+		assert.Equal(t, failure, err)
+		assert.Equal(t, 0, backup.calls, "the primary may have sent the message")
+	}
+}
+
+func TestFailoverClient_RejectionIsNotFailedOver(t *testing.T) {
+	rejected := port.ValidationError{Msg: "webhook rejected message"}
+	primary := &fakeWebhookClient{err: rejected}
+	backup := &fakeWebhookClient{}
+	client := NewFailoverClient(NamedClient{Name: "primary", Client: primary}, NamedClient{Name: "backup", Client: backup})
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+
+	assert.Equal(t, rejected, err)
+	assert.Equal(t, 0, backup.calls)
+}
+
+func TestFailoverClient_ReturnsToPrimaryOnceItRecovers(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	primary := &fakeWebhookClient{err: unavailableError()}
+	backup := &fakeWebhookClient{}
+	guardedPrimary := newTestCircuitBreakerClient(primary, &now)
+	client := NewFailoverClient(NamedClient{Name: "primary", Client: guardedPrimary}, NamedClient{Name: "backup", Client: backup})
+
+	// failures stay with the primary until they open its breaker
+	for i := 0; i < 2; i++ {
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+		assert.ErrorAs(t, err, &port.DependencyError{})
+	}
+	for i := 0; i < 2; i++ {
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, primary.calls, "the open breaker skips the primary")
+	assert.Equal(t, 2, backup.calls)
+	assert.Equal(t, map[string]BreakerStatus{"primary": {State: BreakerOpen, ConsecutiveFailures: 2, OpenedAt: now}}, client.BreakerStatuses())
+
+	primary.err = nil
+	now = now.Add(time.Minute)
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, primary.calls)
+	assert.Equal(t, 2, backup.calls)
+	assert.Equal(t, BreakerClosed, guardedPrimary.BreakerStatus().State)
+}
+
+func TestFailoverClient_AllProvidersUnavailable(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	capped := newTestRateLimitedClient(&fakeWebhookClient{}, RateLimit{DailyCap: 1}, &now)
+	_, _ = capped.SendMessage(context.Background(), testMessage, "")
+
+	t.Run("reports the failure of a called provider", func(t *testing.T) {
+		client := NewFailoverClient(NamedClient{Name: "primary", Client: &fakeWebhookClient{err: refusedError()}}, NamedClient{Name: "backup", Client: capped})
+
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+
+		assert.ErrorAs(t, err, &port.DependencyError{})
+		assert.NotErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("nothing sent", func(t *testing.T) {
+		client := NewFailoverClient(NamedClient{Name: "primary", Client: capped}, NamedClient{Name: "backup", Client: capped})
+
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, 0, client.Available(context.Background()))
+	})
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is the subset of JSONPath needed to locate a value in a provider response: the root $
+// followed by .name, ['name'] and [index] steps, e.g. $.data.messages[0].id.
+type jsonPath struct {
+	expr  string
+	steps []pathStep
+}
+
+type pathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return jsonPath{}, fmt.Errorf("json path %q must start with $", expr)
+	}
+
+	var steps []pathStep
+	rest := expr[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return jsonPath{}, fmt.Errorf("json path %q has an empty name", expr)
+			}
+			steps = append(steps, pathStep{key: key})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return jsonPath{}, fmt.Errorf("json path %q has an unclosed [", expr)
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, pathStep{key: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return jsonPath{}, fmt.Errorf("json path %q has an invalid index [%s]", expr, inner)
+				}
+				steps = append(steps, pathStep{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return jsonPath{}, fmt.Errorf("json path %q: unexpected %q", expr, rest[0])
+		}
+	}
+
+	return jsonPath{expr: expr, steps: steps}, nil
+}
+
+// lookupString decodes the document and returns the string or number the path points at.
+func (p jsonPath) lookupString(document []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+
+	for _, step := range p.steps {
+		switch node := value.(type) {
+		case map[string]any:
+			if step.isIndex {
+				return "", fmt.Errorf("%s: expected an array, found an object", p.expr)
+			}
+			var ok bool
+			if value, ok = node[step.key]; !ok {
+				return "", fmt.Errorf("%s: no field %q", p.expr, step.key)
+			}
+		case []any:
+			if !step.isIndex {
+				return "", fmt.Errorf("%s: expected an object, found an array", p.expr)
+			}
+			if step.index >= len(node) {
+				return "", fmt.Errorf("%s: index %d out of range", p.expr, step.index)
+			}
+			value = node[step.index]
+		default:
+			return "", fmt.Errorf("%s: cannot descend into %v", p.expr, value)
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	default:
+		return "", fmt.Errorf("%s: expected a string or number, found %v", p.expr, value)
+	}
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPath_LookupString(t *testing.T) {
+	document := []byte(`{"data":{"messages":[{"id":"remote-1","sid":12345678901234567890}]},"status":{"ok":true},"meta.id":"m-1"}`)
+
+	tests := []struct {
+		path string
+		want string
+		err  string
+	}{
+		{path: "$.data.messages[0].id", want: "remote-1"},
+		{path: "$['data']['messages'][0]['sid']", want: "12345678901234567890"},
+		{path: `$["meta.id"]`, want: "m-1"},
+		{path: "$.data.missing", err: `no field "missing"`},
+		{path: "$.data.messages[1].id", err: "index 1 out of range"},
+		{path: "$.data[0]", err: "expected an array"},
+		{path: "$.status.ok", err: "expected a string or number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := parseJSONPath(tt.path)
+			assert.NoError(t, err)
+
+			got, err := path.lookupString(document)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseJSONPath_Invalid(t *testing.T) {
+	for _, expr := range []string{"data.id", "$..id", "$.items[", "$.items[-1]", "$.items[x]", "$id"} {
+		_, err := parseJSONPath(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryMargin renews a token this long before it expires, so it does not run out in flight.
+const tokenExpiryMargin = 30 * time.Second
+
+type OAuth2Settings struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// OAuth2ClientCredentials obtains access tokens with the client credentials grant (RFC 6749 section
+// 4.4) and reuses them until shortly before they expire. Concurrent requests wait for a single
+// token refresh instead of each fetching their own.
+type OAuth2ClientCredentials struct {
+	settings OAuth2Settings
+	client   *http.Client
+	now      func() time.Time
+
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func NewOAuth2ClientCredentials(settings OAuth2Settings, timeout time.Duration) *OAuth2ClientCredentials {
+	return &OAuth2ClientCredentials{
+		settings: settings,
+		client:   &http.Client{Timeout: timeout},
+		now:      time.Now,
+	}
+}
+
+func (a *OAuth2ClientCredentials) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate drops the cached token, the next request fetches a new one.
+func (a *OAuth2ClientCredentials) Invalidate() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.token = ""
+	a.expiresAt = time.Time{}
+}
+
+func (a *OAuth2ClientCredentials) accessToken(ctx context.Context) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.token != "" && (a.expiresAt.IsZero() || a.now().Before(a.expiresAt.Add(-tokenExpiryMargin))) {
+		return a.token, nil
+	}
+
+	response, err := a.fetchToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	a.token = response.AccessToken
+	a.expiresAt = time.Time{}
+	if response.ExpiresIn > 0 {
+		a.expiresAt = a.now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+
+	log.Logger.Info().Time("expires_at", a.expiresAt).Msg("Fetched webhook access token")
+	return a.token, nil
+}
+
+func (a *OAuth2ClientCredentials) fetchToken(ctx context.Context) (*tokenResponse, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(a.settings.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.settings.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.settings.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.settings.ClientID), url.QueryEscape(a.settings.ClientSecret))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, port.DependencyError{Msg: "access token request failed", WrappedErr: newNetworkError(err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, port.DependencyError{Msg: "failed to read access token response", WrappedErr: newNetworkError(err)}
+	}
+
+	// even a rejected client is a configuration problem, the message itself is fine and can wait for a fix
+	if !(resp.StatusCode >= 200 && resp.StatusCode <= 299) {
+		return nil, port.DependencyError{Msg: "access token request rejected", WrappedErr: newStatusError(resp, body)}
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return nil, port.DependencyError{
+			Msg:        "invalid access token response",
+			WrappedErr: &Error{Cause: ProtocolCause, StatusCode: resp.StatusCode, Body: string(body), Err: err},
+		}
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"math"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultThrottlePause applies when the provider answers 429 without a Retry-After header.
+const defaultThrottlePause = 10 * time.Second
+
+// ErrRateLimited is returned, before any request is made, when the local quota does not allow
+// another call. The message was not sent and should not be charged an attempt.
+var ErrRateLimited = errors.New("webhook rate limit reached, message not sent")
+
+// QuotaAware is implemented by clients that can tell how many calls they accept right now, so
+// callers can avoid picking up work that would only be rejected.
+type QuotaAware interface {
+	Available(ctx context.Context) int
+}
+
+// DailyCounter keeps the number of requests made to a provider per UTC day in storage shared by all
+// instances. Reserve counts one more request unless limit are already counted, Release takes a
+// reserved one back.
+type DailyCounter interface {
+	Reserve(ctx context.Context, provider string, day time.Time, limit int) (bool, error)
+	Release(ctx context.Context, provider string, day time.Time) error
+	Count(ctx context.Context, provider string, day time.Time) (int, error)
+}
+
+type RateLimit struct {
+	// Rate is the sustained number of requests per second of this instance, 0 disables the token
+	// bucket. Every instance has its own bucket, so replicas together send up to Rate times their number.
+	Rate float64
+	// Burst is the number of requests this instance may send at once before Rate applies.
+	Burst int
+	// DailyCap is the number of requests allowed per UTC day, 0 means unlimited.
+	DailyCap int
+	// Counter, when set, counts DailyCap for all instances together under Provider instead of per instance.
+	Counter  DailyCounter
+	Provider string
+}
+
+// RateLimitedClient throttles a WebhookClient with a token bucket and a daily cap. A 429 from the
+// provider pauses the sends of this instance until its Retry-After has passed.
+type RateLimitedClient struct {
+	next  WebhookClient
+	limit RateLimit
+	now   func() time.Time
+
+	mutex       sync.Mutex
+	tokens      float64
+	refilledAt  time.Time
+	day         time.Time
+	sentToday   int
+	pausedUntil time.Time
+}
+
+func NewRateLimitedClient(next WebhookClient, limit RateLimit) *RateLimitedClient {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return &RateLimitedClient{
+		next:   next,
+		limit:  limit,
+		now:    time.Now,
+		tokens: float64(limit.Burst),
+	}
+}
+
+func (c *RateLimitedClient) SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*WebhookResponse, error) {
+	if err := c.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	response, err := c.next.SendMessage(ctx, message, idempotencyKey)
+	if err != nil {
+		c.pauseIfThrottled(err)
+	}
+
+	return response, err
+}
+
+// Available returns how many requests the daily cap still allows, or 0 while paused after a 429.
+// The token bucket is not taken into account, SendMessage simply waits for it. The shared count is
+// read within ctx, so a slow database cannot hold up the caller past its deadline.
+func (c *RateLimitedClient) Available(ctx context.Context) int {
+	c.mutex.Lock()
+	now := c.now()
+	if now.Before(c.pausedUntil) {
+		c.mutex.Unlock()
+		return 0
+	}
+	if c.limit.DailyCap == 0 {
+		c.mutex.Unlock()
+		return math.MaxInt
+	}
+
+	c.resetDay(now)
+	day, sentToday := c.day, c.sentToday
+	c.mutex.Unlock()
+
+	if c.limit.Counter != nil {
+		count, err := c.limit.Counter.Count(ctx, c.limit.Provider, day)
+		if err != nil {
+			log.Logger.Warn().Err(err).Str("provider", c.limit.Provider).Msg("Failed to read the shared daily count, using the count of this instance")
+		} else {
+			sentToday = count
+		}
+	}
+
+	return max(c.limit.DailyCap-sentToday, 0)
+}
+
+// acquire reserves a token and a share of the daily cap, waiting for the token if necessary.
+func (c *RateLimitedClient) acquire(ctx context.Context) error {
+	c.mutex.Lock()
+
+	now := c.now()
+	if now.Before(c.pausedUntil) {
+		c.mutex.Unlock()
+		return ErrRateLimited
+	}
+
+	c.resetDay(now)
+	if c.limit.Counter == nil && c.limit.DailyCap > 0 && c.sentToday >= c.limit.DailyCap {
+		c.mutex.Unlock()
+		return ErrRateLimited
+	}
+
+	day := c.day
+	wait := c.reserveToken(now)
+	c.sentToday++
+	c.mutex.Unlock()
+
+	if err := c.reserveShared(ctx, day); err != nil {
+		c.giveBack()
+		return err
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// hand the reservation back so cancelled callers do not slow down the next ones
+		c.giveBack()
+		c.releaseShared(context.WithoutCancel(ctx), day)
+		return ErrRateLimited
+	}
+}
+
+// reserveShared takes the request from the daily cap shared with the other instances. When the
+// count cannot be reached the message is not sent rather than risking going over the cap.
+func (c *RateLimitedClient) reserveShared(ctx context.Context, day time.Time) error {
+	if c.limit.Counter == nil || c.limit.DailyCap == 0 {
+		return nil
+	}
+
+	reserved, err := c.limit.Counter.Reserve(ctx, c.limit.Provider, day, c.limit.DailyCap)
+	if err != nil {
+		log.Logger.Error().Err(err).Str("provider", c.limit.Provider).Msg("Failed to reserve a request of the shared daily cap")
+		return ErrRateLimited
+	}
+	if !reserved {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+func (c *RateLimitedClient) releaseShared(ctx context.Context, day time.Time) {
+	if c.limit.Counter == nil || c.limit.DailyCap == 0 {
+		return
+	}
+
+	if err := c.limit.Counter.Release(ctx, c.limit.Provider, day); err != nil {
+		log.Logger.Error().Err(err).Str("provider", c.limit.Provider).Msg("Failed to release a request of the shared daily cap")
+	}
+}
+
+// giveBack returns the token and the share of the daily cap taken by acquire.
+func (c *RateLimitedClient) giveBack() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.tokens = math.Min(float64(c.limit.Burst), c.tokens+1)
+	if c.sentToday > 0 {
+		c.sentToday--
+	}
+}
+
+// reserveToken takes a token, letting the bucket go negative, and returns how long the caller has
+// to wait until that token is actually refilled.
+func (c *RateLimitedClient) reserveToken(now time.Time) time.Duration {
+	if c.limit.Rate <= 0 {
+		return 0
+	}
+
+	if !c.refilledAt.IsZero() {
+		elapsed := now.Sub(c.refilledAt).Seconds()
+		c.tokens = math.Min(float64(c.limit.Burst), c.tokens+elapsed*c.limit.Rate)
+	}
+	c.refilledAt = now
+
+	c.tokens--
+	if c.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-c.tokens / c.limit.Rate * float64(time.Second))
+}
+
+func (c *RateLimitedClient) resetDay(now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if day.Equal(c.day) {
+		return
+	}
+
+	c.day = day
+	c.sentToday = 0
+}
+
+func (c *RateLimitedClient) pauseIfThrottled(err error) {
+	var webhookErr *Error
+	if !errors.As(err, &webhookErr) || webhookErr.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	pause := webhookErr.RetryAfter
+	if pause <= 0 {
+		pause = defaultThrottlePause
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	pausedUntil := c.now().Add(pause)
+	if pausedUntil.After(c.pausedUntil) {
+		c.pausedUntil = pausedUntil
+	}
+
+	log.Logger.Warn().Dur("pause", pause).Msg("Webhook provider throttled requests, pausing sends")
+}
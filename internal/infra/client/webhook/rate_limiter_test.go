@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"context"
+	"math"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/port"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookClient struct {
+	calls int
+	err   error
+}
+
+func (f *fakeWebhookClient) SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*WebhookResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &WebhookResponse{MessageID: "remote-1"}, nil
+}
+
+func newTestRateLimitedClient(next WebhookClient, limit RateLimit, now *time.Time) *RateLimitedClient {
+	client := NewRateLimitedClient(next, limit)
+	client.now = func() time.Time { return *now }
+	return client
+}
+
+func TestRateLimitedClient_DailyCap(t *testing.T) {
+	next := &fakeWebhookClient{}
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	client := newTestRateLimitedClient(next, RateLimit{DailyCap: 2}, &now)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 0, client.Available(context.Background()))
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 2, next.calls)
+
+	now = now.Add(2 * time.Hour)
+	assert.Equal(t, 2, client.Available(context.Background()))
+
+	_, err = client.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, next.calls)
+}
+
+// fakeDailyCounter stands in for the storage the instances share.
+type fakeDailyCounter struct {
+	counts map[string]int
+}
+
+func (f *fakeDailyCounter) key(provider string, day time.Time) string {
+	return provider + "/" + day.Format(time.DateOnly)
+}
+
+func (f *fakeDailyCounter) Reserve(ctx context.Context, provider string, day time.Time, limit int) (bool, error) {
+	if f.counts[f.key(provider, day)] >= limit {
+		return false, nil
+	}
+	f.counts[f.key(provider, day)]++
+	return true, nil
+}
+
+func (f *fakeDailyCounter) Release(ctx context.Context, provider string, day time.Time) error {
+	f.counts[f.key(provider, day)]--
+	return nil
+}
+
+func (f *fakeDailyCounter) Count(ctx context.Context, provider string, day time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.counts[f.key(provider, day)], nil
+}
+
+func TestRateLimitedClient_DailyCapSharedBetweenInstances(t *testing.T) {
+	counter := &fakeDailyCounter{counts: map[string]int{}}
+	limit := RateLimit{DailyCap: 2, Counter: counter, Provider: "default"}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	nextA, nextB := &fakeWebhookClient{}, &fakeWebhookClient{}
+	instanceA := newTestRateLimitedClient(nextA, limit, &now)
+	instanceB := newTestRateLimitedClient(nextB, limit, &now)
+
+	_, err := instanceA.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, instanceB.Available(context.Background()))
+
+	_, err = instanceB.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+
+	_, err = instanceA.SendMessage(context.Background(), testMessage, "")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 1, nextA.calls)
+	assert.Equal(t, 0, instanceA.Available(context.Background()))
+
+	now = now.Add(12 * time.Hour)
+	assert.Equal(t, 2, instanceB.Available(context.Background()))
+}
+
+func TestRateLimitedClient_AvailableReadsSharedCountWithinContext(t *testing.T) {
+	counter := &fakeDailyCounter{counts: map[string]int{}}
+	limit := RateLimit{DailyCap: 2, Counter: counter, Provider: "default"}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	instanceA := newTestRateLimitedClient(&fakeWebhookClient{}, limit, &now)
+	instanceB := newTestRateLimitedClient(&fakeWebhookClient{}, limit, &now)
+
+	_, err := instanceA.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the shared count is not read once the tick is over, the local one is used instead
+	assert.Equal(t, 2, instanceB.Available(ctx))
+	assert.Equal(t, 1, instanceB.Available(context.Background()))
+}
+
+func TestRateLimitedClient_TokenBucketWaits(t *testing.T) {
+	next := &fakeWebhookClient{}
+	client := NewRateLimitedClient(next, RateLimit{Rate: 20, Burst: 2})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+		assert.NoError(t, err)
+	}
+
+	// the burst goes out at once, the remaining two wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, 4, next.calls)
+	assert.Equal(t, math.MaxInt, client.Available(context.Background()))
+}
+
+func TestRateLimitedClient_WaitRespectsContext(t *testing.T) {
+	next := &fakeWebhookClient{}
+	client := NewRateLimitedClient(next, RateLimit{Rate: 0.1, Burst: 1, DailyCap: 10})
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = client.SendMessage(ctx, testMessage, "")
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 1, next.calls)
+	assert.Equal(t, 9, client.Available(context.Background()))
+}
+
+func TestRateLimitedClient_PausesOnTooManyRequests(t *testing.T) {
+	throttled := port.DependencyError{
+		Msg:        "webhook unavailable",
+		WrappedErr: &Error{Cause: ProtocolCause, StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute},
+	}
+	next := &fakeWebhookClient{err: throttled}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := newTestRateLimitedClient(next, RateLimit{}, &now)
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+	assert.ErrorIs(t, err, throttled)
+	assert.Equal(t, 0, client.Available(context.Background()))
+
+	next.err = nil
+	_, err = client.SendMessage(context.Background(), testMessage, "")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 1, next.calls)
+
+	now = now.Add(time.Minute)
+	_, err = client.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestRateLimitedClient_DefaultPauseWithoutRetryAfter(t *testing.T) {
+	next := &fakeWebhookClient{err: &Error{Cause: ProtocolCause, StatusCode: http.StatusTooManyRequests}}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := newTestRateLimitedClient(next, RateLimit{}, &now)
+
+	_, _ = client.SendMessage(context.Background(), testMessage, "")
+
+	now = now.Add(defaultThrottlePause - time.Second)
+	assert.Equal(t, 0, client.Available(context.Background()))
+	now = now.Add(time.Second)
+	assert.Equal(t, math.MaxInt, client.Available(context.Background()))
+}
@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/log"
+	"slices"
+	"strings"
+)
+
+// DefaultProvider is the name of the provider configured by the top-level webhook settings.
+const DefaultProvider = "default"
+
+// Rule routes the messages it matches to Provider. Every criterion that is set must match, a rule
+// without criteria matches every message.
+type Rule struct {
+	Provider string
+	// PhonePrefixes match the start of the E.164 number, with or without the leading +, e.g. "+90".
+	PhonePrefixes []string
+	// Tags match messages carrying at least one of them.
+	Tags       []string
+	Priorities []priority.MessagePriority
+}
+
+func (r Rule) matches(message *entity.MessagesEntity) bool {
+	if len(r.PhonePrefixes) > 0 && !slices.ContainsFunc(r.PhonePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(strings.TrimPrefix(message.Phone, "+"), strings.TrimPrefix(prefix, "+"))
+	}) {
+		return false
+	}
+	if len(r.Tags) > 0 && !slices.ContainsFunc(r.Tags, func(tag string) bool {
+		return slices.Contains(message.Tags, tag)
+	}) {
+		return false
+	}
+	if len(r.Priorities) > 0 && !slices.Contains(r.Priorities, message.Priority) {
+		return false
+	}
+	return true
+}
+
+// MultiBreakerAware is implemented by clients spreading messages over several providers, each
+// guarded by its own circuit breaker.
+type MultiBreakerAware interface {
+	BreakerStatuses() map[string]BreakerStatus
+}
+
+// Routing describes how a client spreads messages over providers: through the Provider of the first
+// rule a message matches, or through Fallback. Available holds how many messages each provider with
+// a quota accepts right now, 0 while its daily cap is used up or its breaker is open.
+type Routing struct {
+	Rules     []Rule
+	Fallback  string
+	Available map[string]int
+}
+
+// RouteAware is implemented by clients that route messages to providers with quotas of their own,
+// so callers can pick up only the work each provider accepts.
+type RouteAware interface {
+	Routing(ctx context.Context) Routing
+}
+
+// Router delivers every message through the provider of the first rule it matches, or through the
+// fallback provider when no rule matches.
+type Router struct {
+	providers map[string]WebhookClient
+	rules     []Rule
+	fallback  string
+}
+
+// NewRouter checks that the rules and the fallback only name known providers.
+func NewRouter(providers map[string]WebhookClient, rules []Rule, fallback string) (*Router, error) {
+	if _, ok := providers[fallback]; !ok {
+		return nil, fmt.Errorf("unknown fallback provider %q", fallback)
+	}
+	for i, rule := range rules {
+		if _, ok := providers[rule.Provider]; !ok {
+			return nil, fmt.Errorf("routing rule %d uses unknown provider %q", i+1, rule.Provider)
+		}
+		for _, p := range rule.Priorities {
+			if !p.Valid() {
+				return nil, fmt.Errorf("routing rule %d uses unknown priority %q", i+1, p)
+			}
+		}
+	}
+
+	return &Router{providers: providers, rules: rules, fallback: fallback}, nil
+}
+
+func (r *Router) SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*WebhookResponse, error) {
+	provider := r.Route(message)
+	log.Logger.Debug().Str("message_id", message.Id).Str("provider", provider).Msg("Routing message")
+
+	return r.providers[provider].SendMessage(ctx, message, idempotencyKey)
+}
+
+// Route returns the name of the provider that delivers the message.
+func (r *Router) Route(message *entity.MessagesEntity) string {
+	for _, rule := range r.rules {
+		if rule.matches(message) {
+			return rule.Provider
+		}
+	}
+	return r.fallback
+}
+
+// Available adds up the quotas of all providers, the most messages the router accepts at once.
+// Routing tells how many of them each provider takes.
+func (r *Router) Available(ctx context.Context) int {
+	clients := make([]WebhookClient, 0, len(r.providers))
+	for _, client := range r.providers {
+		clients = append(clients, client)
+	}
+	return totalAvailable(ctx, clients)
+}
+
+func (r *Router) Routing(ctx context.Context) Routing {
+	available := make(map[string]int)
+	for name, client := range r.providers {
+		if quota, ok := client.(QuotaAware); ok {
+			if providerAvailable := quota.Available(ctx); providerAvailable < math.MaxInt {
+				available[name] = max(providerAvailable, 0)
+			}
+		}
+	}
+	return Routing{Rules: r.rules, Fallback: r.fallback, Available: available}
+}
+
+// totalAvailable adds up the quotas of the clients, a client without a quota makes it unlimited.
+func totalAvailable(ctx context.Context, clients []WebhookClient) int {
+	total := 0
+	for _, client := range clients {
+		quota, ok := client.(QuotaAware)
+		if !ok {
+			return math.MaxInt
+		}
+		available := quota.Available(ctx)
+		if available > math.MaxInt-total {
+			return math.MaxInt
+		}
+		total += max(available, 0)
+	}
+	return total
+}
+
+func (r *Router) BreakerStatuses() map[string]BreakerStatus {
+	statuses := make(map[string]BreakerStatus)
+	for name, client := range r.providers {
+		switch client := client.(type) {
+		case BreakerAware:
+			statuses[name] = client.BreakerStatus()
+		case MultiBreakerAware:
+			// a failover chain reports the breakers of its members
+			for member, status := range client.BreakerStatuses() {
+				statuses[member] = status
+			}
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"context"
+	"math"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRules = []Rule{
+	{Provider: "otp", Priorities: []priority.MessagePriority{priority.CRITICAL}},
+	{Provider: "marketing", PhonePrefixes: []string{"+90"}, Tags: []string{"campaign", "newsletter"}},
+	{Provider: "turkey", PhonePrefixes: []string{"90"}},
+}
+
+func newTestRouter(t *testing.T, providers map[string]WebhookClient) *Router {
+	router, err := NewRouter(providers, testRules, DefaultProvider)
+	assert.NoError(t, err)
+	return router
+}
+
+func TestRouter_Route(t *testing.T) {
+	router := newTestRouter(t, map[string]WebhookClient{
+		DefaultProvider: &fakeWebhookClient{},
+		"otp":           &fakeWebhookClient{},
+		"marketing":     &fakeWebhookClient{},
+		"turkey":        &fakeWebhookClient{},
+	})
+
+	tests := []struct {
+		name    string
+		message *entity.MessagesEntity
+		want    string
+	}{
+		{name: "priority", message: &entity.MessagesEntity{Phone: "+905551234567", Priority: priority.CRITICAL}, want: "otp"},
+		{name: "prefix and tag", message: &entity.MessagesEntity{Phone: "+905551234567", Priority: priority.BULK, Tags: []string{"newsletter"}}, want: "marketing"},
+		{name: "tag without prefix", message: &entity.MessagesEntity{Phone: "+4915112345678", Priority: priority.BULK, Tags: []string{"campaign"}}, want: DefaultProvider},
+		{name: "prefix", message: &entity.MessagesEntity{Phone: "+905551234567", Priority: priority.NORMAL}, want: "turkey"},
+		{name: "no rule", message: &entity.MessagesEntity{Phone: "+4915112345678", Priority: priority.NORMAL}, want: DefaultProvider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, router.Route(tt.message))
+		})
+	}
+}
+
+func TestRouter_SendsThroughRoutedProvider(t *testing.T) {
+	fallback := &fakeWebhookClient{}
+	turkey := &fakeWebhookClient{}
+	router := newTestRouter(t, map[string]WebhookClient{
+		DefaultProvider: fallback,
+		"otp":           &fakeWebhookClient{},
+		"marketing":     &fakeWebhookClient{},
+		"turkey":        turkey,
+	})
+
+	_, err := router.SendMessage(context.Background(), &entity.MessagesEntity{Phone: "+905551234567"}, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, turkey.calls)
+	assert.Equal(t, 0, fallback.calls)
+}
+
+func TestNewRouter_RejectsUnknownNames(t *testing.T) {
+	providers := map[string]WebhookClient{DefaultProvider: &fakeWebhookClient{}}
+
+	_, err := NewRouter(providers, nil, "missing")
+	assert.ErrorContains(t, err, `unknown fallback provider "missing"`)
+
+	_, err = NewRouter(providers, []Rule{{Provider: "missing"}}, DefaultProvider)
+	assert.ErrorContains(t, err, `routing rule 1 uses unknown provider "missing"`)
+
+	_, err = NewRouter(providers, []Rule{{Provider: DefaultProvider, Priorities: []priority.MessagePriority{"urgent"}}}, DefaultProvider)
+	assert.ErrorContains(t, err, `unknown priority "urgent"`)
+}
+
+func TestRouter_QuotaAndBreakers(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	capped := newTestRateLimitedClient(&fakeWebhookClient{}, RateLimit{DailyCap: 3}, &now)
+	breaker := newTestCircuitBreakerClient(newTestRateLimitedClient(&fakeWebhookClient{}, RateLimit{DailyCap: 2}, &now), &now)
+
+	router, err := NewRouter(map[string]WebhookClient{DefaultProvider: capped, "turkey": breaker}, nil, DefaultProvider)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 5, router.Available(context.Background()))
+	assert.Equal(t, map[string]BreakerStatus{"turkey": {State: BreakerClosed}}, router.BreakerStatuses())
+
+	router.providers["unlimited"] = &fakeWebhookClient{}
+	assert.Equal(t, math.MaxInt, router.Available(context.Background()))
+}
+
+func TestRouter_Routing(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	capped := newTestRateLimitedClient(&fakeWebhookClient{}, RateLimit{DailyCap: 3}, &now)
+	breaker := newTestCircuitBreakerClient(&fakeWebhookClient{err: unavailableError()}, &now)
+	for i := 0; i < 2; i++ {
+		_, _ = breaker.SendMessage(context.Background(), testMessage, "")
+	}
+
+	router := newTestRouter(t, map[string]WebhookClient{
+		DefaultProvider: &fakeWebhookClient{},
+		"otp":           capped,
+		"marketing":     breaker,
+		"turkey":        &fakeWebhookClient{},
+	})
+
+	// providers without a quota are left out, the open breaker accepts nothing
+	assert.Equal(t, Routing{
+		Rules:     testRules,
+		Fallback:  DefaultProvider,
+		Available: map[string]int{"otp": 3, "marketing": 0},
+	}, router.Routing(context.Background()))
+}
+
+func TestRouter_ReportsBreakersOfFailoverChains(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	primary := newTestCircuitBreakerClient(&fakeWebhookClient{}, &now)
+	backup := newTestCircuitBreakerClient(&fakeWebhookClient{}, &now)
+
+	router, err := NewRouter(map[string]WebhookClient{
+		DefaultProvider: NewFailoverClient(NamedClient{Name: DefaultProvider, Client: primary}, NamedClient{Name: "backup", Client: backup}),
+		"backup":        backup,
+	}, nil, DefaultProvider)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]BreakerStatus{
+		DefaultProvider: {State: BreakerClosed},
+		"backup":        {State: BreakerClosed},
+	}, router.BreakerStatuses())
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+const defaultRequestBody = `{"to":{{json .Phone}},"content":{{json .Content}}}`
+
+// RequestTemplateSettings describes the API of a provider. Body and the header values are Go
+// templates executed with TemplateData, MessageIDPath is a JSONPath into the response body.
+// Empty fields fall back to the default request: a POST of {"to","content"} answered with messageId.
+// Only a MessageIDPath that is set makes the id mandatory, the default messageId may be missing.
+type RequestTemplateSettings struct {
+	Method        string
+	Headers       map[string]string
+	Body          string
+	MessageIDPath string
+}
+
+// TemplateData is the message as seen by request templates.
+type TemplateData struct {
+	ID             string
+	Phone          string
+	Content        string
+	Priority       string
+	Tags           []string
+	Locale         string
+	IdempotencyKey string
+}
+
+var templateFuncs = template.FuncMap{
+	// json quotes a value for use inside a JSON body, e.g. {"text":{{json .Content}}}
+	"json": func(value any) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+	"trimPrefix": strings.TrimPrefix,
+	"join":       strings.Join,
+}
+
+// RequestTemplate renders the requests of a provider whose API differs from the default one.
+type RequestTemplate struct {
+	method  string
+	headers map[string]*template.Template
+	body    *template.Template
+	// messageIDPath is nil unless configured, the id is then read like the default response
+	messageIDPath *jsonPath
+}
+
+// NewRequestTemplate parses the templates and renders a sample message, so mistakes such as
+// unknown fields surface at startup rather than on the first send.
+func NewRequestTemplate(settings RequestTemplateSettings) (*RequestTemplate, error) {
+	method := strings.ToUpper(settings.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	body := settings.Body
+	if body == "" {
+		body = defaultRequestBody
+	}
+	bodyTemplate, err := parseTemplate("body", body)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]*template.Template, len(settings.Headers))
+	for name, value := range settings.Headers {
+		if headers[name], err = parseTemplate("header "+name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	var messageIDPath *jsonPath
+	if settings.MessageIDPath != "" {
+		path, err := parseJSONPath(settings.MessageIDPath)
+		if err != nil {
+			return nil, err
+		}
+		messageIDPath = &path
+	}
+
+	requestTemplate := &RequestTemplate{method: method, headers: headers, body: bodyTemplate, messageIDPath: messageIDPath}
+	sample := &entity.MessagesEntity{Id: "1", Phone: "+905551234567", Content: "sample", Priority: priority.NORMAL, Tags: []string{"sample"}}
+	if _, _, err := requestTemplate.render(sample, "1-attempt-1"); err != nil {
+		return nil, err
+	}
+
+	return requestTemplate, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	parsed, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return parsed, nil
+}
+
+// render executes the body and header templates for the message.
+func (t *RequestTemplate) render(message *entity.MessagesEntity, idempotencyKey string) ([]byte, http.Header, error) {
+	data := TemplateData{
+		ID:             message.Id,
+		Phone:          message.Phone,
+		Content:        message.Content,
+		Priority:       string(message.Priority),
+		Tags:           message.Tags,
+		Locale:         message.Locale,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	var body bytes.Buffer
+	if err := t.body.Execute(&body, data); err != nil {
+		return nil, nil, fmt.Errorf("failed to render body template: %w", err)
+	}
+
+	headers := make(http.Header, len(t.headers))
+	var value strings.Builder
+	for name, headerTemplate := range t.headers {
+		value.Reset()
+		if err := headerTemplate.Execute(&value, data); err != nil {
+			return nil, nil, fmt.Errorf("failed to render header %s: %w", name, err)
+		}
+		headers.Set(name, value.String())
+	}
+
+	return body.Bytes(), headers, nil
+}
+
+// messageID extracts the provider's id of the message from the response body. Without a configured
+// path the id is optional, as for the default request.
+func (t *RequestTemplate) messageID(body []byte) (string, error) {
+	if t.messageIDPath == nil {
+		var response WebhookResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return "", err
+		}
+		return response.MessageID, nil
+	}
+	return t.messageIDPath.lookupString(body)
+}
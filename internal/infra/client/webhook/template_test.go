@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestTemplate_Defaults(t *testing.T) {
+	requestTemplate, err := NewRequestTemplate(RequestTemplateSettings{})
+	assert.NoError(t, err)
+
+	body, headers, err := requestTemplate.render(testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", requestTemplate.method)
+	assert.JSONEq(t, `{"to":"+905551234567","content":"Hello"}`, string(body))
+	assert.Empty(t, headers)
+}
+
+func TestRequestTemplate_Render(t *testing.T) {
+	requestTemplate, err := NewRequestTemplate(RequestTemplateSettings{
+		Method:  "put",
+		Headers: map[string]string{"X-Reference": "{{.ID}}", "X-Tags": `{{join .Tags ","}}`},
+		Body:    `{"msisdn":{{json (trimPrefix .Phone "+")}},"text":{{json .Content}},"ref":{{json .IdempotencyKey}}}`,
+	})
+	assert.NoError(t, err)
+
+	message := *testMessage
+	message.Content = `Say "hi"`
+	message.Tags = []string{"otp", "login"}
+	body, headers, err := requestTemplate.render(&message, "42-attempt-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", requestTemplate.method)
+	assert.JSONEq(t, `{"msisdn":"905551234567","text":"Say \"hi\"","ref":"42-attempt-1"}`, string(body))
+	assert.Equal(t, "42", headers.Get("X-Reference"))
+	assert.Equal(t, "otp,login", headers.Get("X-Tags"))
+}
+
+func TestNewRequestTemplate_RejectsInvalidTemplates(t *testing.T) {
+	_, err := NewRequestTemplate(RequestTemplateSettings{Body: `{"to":{{.Phone}`})
+	assert.ErrorContains(t, err, "invalid body template")
+
+	_, err = NewRequestTemplate(RequestTemplateSettings{Body: `{"to":{{json .Recipient}}}`})
+	assert.ErrorContains(t, err, "failed to render body template")
+
+	_, err = NewRequestTemplate(RequestTemplateSettings{Headers: map[string]string{"X-Unknown": "{{lower .ID}}"}})
+	assert.ErrorContains(t, err, "invalid header X-Unknown template")
+
+	_, err = NewRequestTemplate(RequestTemplateSettings{MessageIDPath: "messageId"})
+	assert.ErrorContains(t, err, "must start with $")
+}
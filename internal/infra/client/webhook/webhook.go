@@ -4,24 +4,83 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
-	"fmt"
 	"io"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/port"
+	"message-scheduler/signature"
 	"net"
 	"net/http"
 	"time"
 )
 
+// IdempotencyHeader carries the idempotency key, so the provider can drop a request it has already
+// processed when a send is repeated after a crash.
+const IdempotencyHeader = "Idempotency-Key"
+
 type WebhookClient interface {
-	SendMessage(ctx context.Context, to string, content string) (*WebhookResponse, error)
+	// SendMessage delivers the message. Repeating a call with the same idempotencyKey must not deliver
+	// the message twice, as far as the provider honours the key.
+	SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*WebhookResponse, error)
 }
 
 type Client struct {
-	client *http.Client
-	url    string
+	client   *http.Client
+	url      string
+	provider string
+	signer   *signature.Signer
+	auth     Authenticator
+	template *RequestTemplate
+}
+
+// ClientOption configures optional behaviour of the webhook Client.
+type ClientOption func(*Client)
+
+// WithProviderName names the provider behind the client, it is reported in every WebhookResponse.
+func WithProviderName(name string) ClientOption {
+	return func(c *Client) {
+		c.provider = name
+	}
+}
+
+// WithRequestTemplate replaces the default request and response format with the provider's own.
+func WithRequestTemplate(requestTemplate *RequestTemplate) ClientOption {
+	return func(c *Client) {
+		c.template = requestTemplate
+	}
+}
+
+// WithSigner signs every request body, see the signature package for the header format.
+func WithSigner(signer *signature.Signer) ClientOption {
+	return func(c *Client) {
+		c.signer = signer
+	}
 }
 
-func NewWebhookClient(url string, timeout time.Duration) *Client {
+// WithAuthenticator adds the authenticator's credentials to every request.
+func WithAuthenticator(auth Authenticator) ClientOption {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithClientCertificate presents the certificate when the webhook asks for one, i.e. for mutual TLS.
+func WithClientCertificate(certificate tls.Certificate) ClientOption {
+	return func(c *Client) {
+		tlsConfig := c.client.Transport.(*http.Transport).TLSClientConfig
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+}
+
+// WithRootCAs trusts the given authorities instead of the system ones, for webhooks with a private CA.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		c.client.Transport.(*http.Transport).TLSClientConfig.RootCAs = pool
+	}
+}
+
+func NewWebhookClient(url string, timeout time.Duration, opts ...ClientOption) *Client {
 	transport := &http.Transport{
 		MaxIdleConns:          100,
 		MaxIdleConnsPerHost:   10,
@@ -44,10 +103,15 @@ func NewWebhookClient(url string, timeout time.Duration) *Client {
 		Timeout:   timeout,
 	}
 
-	return &Client{
+	webhookClient := &Client{
 		client: client,
 		url:    url,
 	}
+	for _, opt := range opts {
+		opt(webhookClient)
+	}
+
+	return webhookClient
 }
 
 type WebhookRequest struct {
@@ -58,44 +122,105 @@ type WebhookRequest struct {
 type WebhookResponse struct {
 	Message   string `json:"message"`
 	MessageID string `json:"messageId"`
+	// Provider is the name of the provider that accepted the message, set by the client.
+	Provider string `json:"-"`
 }
 
-func (c *Client) SendMessage(ctx context.Context, to string, content string) (*WebhookResponse, error) {
-	reqBody := WebhookRequest{
-		To:      to,
-		Content: content,
-	}
-
-	reqBodyBytes, err := json.Marshal(reqBody)
+func (c *Client) SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*WebhookResponse, error) {
+	method, reqBodyBytes, headers, err := c.buildRequest(message, idempotencyKey)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewBuffer(reqBodyBytes))
+	req, err := http.NewRequestWithContext(ctx, method, c.url, bytes.NewBuffer(reqBodyBytes))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyHeader, idempotencyKey)
+	}
+	if c.signer != nil {
+		req.Header.Set(signature.Header, c.signer.Sign(reqBodyBytes))
+	}
+	if c.auth != nil {
+		if err := c.auth.Authenticate(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, port.DependencyError{Msg: "webhook request failed", WrappedErr: newNetworkError(err)}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, port.DependencyError{Msg: "failed to read response body", WrappedErr: newNetworkError(err)}
 	}
 
 	if !(resp.StatusCode >= 200 && resp.StatusCode <= 299) {
-		return nil, fmt.Errorf("status_code=%d, body=%s", resp.StatusCode, string(body))
+		statusErr := newStatusError(resp, body)
+		if invalidator, ok := c.auth.(tokenInvalidator); ok && resp.StatusCode == http.StatusUnauthorized {
+			// the cached token was revoked or expired early, the next attempt fetches a fresh one
+			invalidator.Invalidate()
+		}
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return nil, port.DependencyError{Msg: "webhook rejected credentials", WrappedErr: statusErr}
+		}
+		if statusErr.Retryable() {
+			return nil, port.DependencyError{Msg: "webhook unavailable", WrappedErr: statusErr}
+		}
+		return nil, port.ValidationError{Msg: "webhook rejected message", WrappedErr: statusErr}
+	}
+
+	webhookResp, err := c.parseResponse(body)
+	if err != nil {
+		// The webhook accepted the request, so the message may have been delivered; retrying could send it twice.
+		return nil, port.ValidationError{
+			Msg:        "failed to parse response body",
+			WrappedErr: &Error{Cause: ProtocolCause, StatusCode: resp.StatusCode, Body: string(body), Err: err},
+		}
+	}
+
+	webhookResp.Provider = c.provider
+	return webhookResp, nil
+}
+
+// buildRequest returns the method, body and extra headers of the request delivering the message.
+func (c *Client) buildRequest(message *entity.MessagesEntity, idempotencyKey string) (string, []byte, http.Header, error) {
+	if c.template != nil {
+		body, headers, err := c.template.render(message, idempotencyKey)
+		if err != nil {
+			return "", nil, nil, port.ValidationError{Msg: "failed to render webhook request", WrappedErr: err}
+		}
+		return c.template.method, body, headers, nil
+	}
+
+	body, err := json.Marshal(WebhookRequest{To: message.Phone, Content: message.Content})
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return http.MethodPost, body, nil, nil
+}
+
+// parseResponse reads the provider's id of the message from the body of a successful response.
+func (c *Client) parseResponse(body []byte) (*WebhookResponse, error) {
+	if c.template != nil {
+		messageID, err := c.template.messageID(body)
+		if err != nil {
+			return nil, err
+		}
+		return &WebhookResponse{MessageID: messageID}, nil
 	}
 
 	var webhookResp WebhookResponse
 	if err := json.Unmarshal(body, &webhookResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+		return nil, err
 	}
-
 	return &webhookResp, nil
 }
@@ -0,0 +1,219 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/port"
+	"message-scheduler/signature"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testMessage = &entity.MessagesEntity{Id: "42", Phone: "+905551234567", Content: "Hello"}
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewWebhookClient(server.URL, time.Second)
+}
+
+func TestSendMessage_Success(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"message":"Accepted","messageId":"remote-1"}`))
+	})
+
+	resp, err := client.SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "remote-1", resp.MessageID)
+}
+
+func TestSendMessage_ClientErrorIsPermanent(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`invalid phone`))
+	})
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+
+	var validationErr port.ValidationError
+	var webhookErr *Error
+	assert.ErrorAs(t, err, &validationErr)
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, ProtocolCause, webhookErr.Cause)
+	assert.Equal(t, http.StatusBadRequest, webhookErr.StatusCode)
+	assert.False(t, webhookErr.Retryable())
+}
+
+func TestSendMessage_RejectedCredentialsAreRetryable(t *testing.T) {
+	for _, statusCode := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+		})
+		client.auth = BearerToken("revoked")
+
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+
+		var webhookErr *Error
+		assert.ErrorAs(t, err, &port.DependencyError{})
+		assert.ErrorAs(t, err, &webhookErr)
+		assert.Equal(t, statusCode, webhookErr.StatusCode)
+		assert.True(t, webhookErr.Retryable())
+	}
+}
+
+func TestSendMessage_RetryableStatuses(t *testing.T) {
+	for _, statusCode := range []int{http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(statusCode)
+		})
+
+		_, err := client.SendMessage(context.Background(), testMessage, "")
+
+		var dependencyErr port.DependencyError
+		assert.ErrorAs(t, err, &dependencyErr)
+		retryAfter, ok := RetryAfter(err)
+		assert.True(t, ok)
+		assert.Equal(t, 2*time.Minute, retryAfter)
+	}
+}
+
+func TestSendMessage_Timeout(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	client.client.Timeout = 50 * time.Millisecond
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+
+	var dependencyErr port.DependencyError
+	var webhookErr *Error
+	assert.ErrorAs(t, err, &dependencyErr)
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, NetworkCause, webhookErr.Cause)
+	assert.True(t, webhookErr.Timeout)
+	assert.True(t, webhookErr.Retryable())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Minute, parseRetryAfter("Wed, 01 Jan 2025 10:01:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Wed, 01 Jan 2025 09:00:00 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+}
+
+func TestSendMessage_SignsRequest(t *testing.T) {
+	signer, err := signature.NewSigner("secret")
+	assert.NoError(t, err)
+	verifier, err := signature.NewVerifier(time.Minute, "secret")
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.VerifyRequest(r); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":"Accepted","messageId":"remote-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := NewWebhookClient(server.URL, time.Second, WithSigner(signer)).SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "remote-1", resp.MessageID)
+}
+
+func TestSendMessage_UnsignedByDefault(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(signature.Header))
+		_, _ = w.Write([]byte(`{"message":"Accepted","messageId":"remote-1"}`))
+	})
+
+	_, err := client.SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+}
+
+func TestSendMessage_IdempotencyKey(t *testing.T) {
+	var received []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(IdempotencyHeader))
+		_, _ = w.Write([]byte(`{"message":"Accepted","messageId":"remote-1"}`))
+	})
+
+	_, err := client.SendMessage(context.Background(), testMessage, "message-42-attempt-1")
+	assert.NoError(t, err)
+	_, err = client.SendMessage(context.Background(), testMessage, "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"message-42-attempt-1", ""}, received)
+}
+
+func TestSendMessage_RequestTemplate(t *testing.T) {
+	requestTemplate, err := NewRequestTemplate(RequestTemplateSettings{
+		Method:        http.MethodPut,
+		Headers:       map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		Body:          `to={{trimPrefix .Phone "+"}}&text={{.Content}}`,
+		MessageIDPath: "$.messages[0].id",
+	})
+	assert.NoError(t, err)
+
+	var got *http.Request
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"messages":[{"id":"remote-7"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	resp, err := NewWebhookClient(server.URL, time.Second, WithRequestTemplate(requestTemplate)).SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "remote-7", resp.MessageID)
+	assert.Equal(t, http.MethodPut, got.Method)
+	assert.Equal(t, "application/x-www-form-urlencoded", got.Header.Get("Content-Type"))
+	assert.Equal(t, "to=905551234567&text=Hello", string(gotBody))
+}
+
+func TestSendMessage_DefaultMessageIdMayBeMissing(t *testing.T) {
+	requestTemplate, err := NewRequestTemplate(RequestTemplateSettings{Body: `{"msisdn":{{json .Phone}},"text":{{json .Content}}}`})
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"queued"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	response, err := NewWebhookClient(server.URL, time.Second, WithRequestTemplate(requestTemplate)).SendMessage(context.Background(), testMessage, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", response.MessageID)
+}
+
+func TestSendMessage_MissingMessageIdIsNotRetried(t *testing.T) {
+	requestTemplate, err := NewRequestTemplate(RequestTemplateSettings{MessageIDPath: "$.data.id"})
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err = NewWebhookClient(server.URL, time.Second, WithRequestTemplate(requestTemplate)).SendMessage(context.Background(), testMessage, "")
+
+	var validationErr port.ValidationError
+	var webhookErr *Error
+	assert.ErrorAs(t, err, &validationErr)
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, ProtocolCause, webhookErr.Cause)
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"message-scheduler/log"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// AdvisoryLock implements port.LeaderLock with a session level postgres advisory lock. The lock lives
+// as long as the dedicated connection that acquired it, so it is released by postgres when the holder
+// crashes or loses its connection.
+type AdvisoryLock struct {
+	db    *gorm.DB
+	key   int64
+	mutex sync.Mutex
+	conn  *sql.Conn
+}
+
+func NewAdvisoryLock(db *gorm.DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: db, key: key}
+}
+
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn != nil {
+		return true, nil
+	}
+
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return false, fmt.Errorf("failed to get database handle: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to open advisory lock connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		discard(conn)
+		return false, fmt.Errorf("failed to acquire advisory lock %d: %w", l.key, err)
+	}
+
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+func (l *AdvisoryLock) Check(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn == nil {
+		return fmt.Errorf("advisory lock %d is not held", l.key)
+	}
+
+	if err := l.conn.PingContext(ctx); err != nil {
+		discard(l.conn)
+		l.conn = nil
+		return fmt.Errorf("advisory lock %d connection lost: %w", l.key, err)
+	}
+
+	return nil
+}
+
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	conn := l.conn
+	l.conn = nil
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		discard(conn)
+		return fmt.Errorf("failed to release advisory lock %d: %w", l.key, err)
+	}
+
+	return conn.Close()
+}
+
+// discard closes the underlying session instead of returning it to the pool, which guarantees that
+// postgres drops any advisory lock still held by it.
+func discard(conn *sql.Conn) {
+	err := conn.Raw(func(any) error { return driver.ErrBadConn })
+	if err != nil && err != driver.ErrBadConn {
+		log.Logger.Warn().Err(err).Msg("Failed to discard advisory lock connection")
+	}
+	_ = conn.Close()
+}
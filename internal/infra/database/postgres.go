@@ -11,7 +11,9 @@ import (
 )
 
 func NewPostgresDB(conf config.PostgresConfig) *gorm.DB {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+	// the session works in UTC whatever the server's zone, so timestamps read back and compared
+	// against now() never depend on where the database runs
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=UTC",
 		conf.WriteHost, conf.WritePort, conf.User, conf.Password, conf.DbName)
 
 	var err error
@@ -2,22 +2,94 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"message-scheduler/internal/domain/entity"
 	"message-scheduler/internal/domain/types/status"
 	"message-scheduler/internal/infra/repository/models"
 	"message-scheduler/log"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MessagesRepository interface {
-	Save(ctx context.Context, message *entity.MessagesEntity) error
+	Create(ctx context.Context, message *entity.MessagesEntity) error
+	SaveBatch(ctx context.Context, messages []*entity.MessagesEntity) error
+	SaveClaimed(ctx context.Context, message *entity.MessagesEntity, owner string) (bool, error)
 	GetUnsentMessages(ctx context.Context, recordLimit int) ([]*entity.MessagesEntity, error)
+	ClaimMessages(ctx context.Context, claim ClaimOptions) ([]*entity.MessagesEntity, error)
+	ReleaseExpiredLeases(ctx context.Context) (int64, error)
 	GetSentMessages(ctx context.Context, recordLimit int) ([]*entity.MessagesEntity, error)
+	GetMessage(ctx context.Context, id string) (*entity.MessagesEntity, error)
+	GetMessageHistory(ctx context.Context, id string) ([]*entity.MessageHistoryEntity, error)
+	SearchMessages(ctx context.Context, filter MessageFilter) ([]*entity.MessagesEntity, error)
+	UpdatePending(ctx context.Context, message *entity.MessagesEntity) (bool, error)
+	GetMessageByRemoteID(ctx context.Context, provider string, remoteMessageID string) (*entity.MessagesEntity, error)
+	RecordDelivery(ctx context.Context, message *entity.MessagesEntity) (bool, error)
 }
 
+// ErrMessageNotFound is returned by GetMessage and GetMessageByRemoteID when no message matches.
+var ErrMessageNotFound = errors.New("message not found")
+
+// MessageFilter narrows SearchMessages, zero fields are ignored. Results are ordered newest first by
+// created_at, id and start after the After position when it is set.
+type MessageFilter struct {
+	Statuses        []status.MessageStatus
+	Phone           string
+	RemoteMessageID string
+	// Tags must all be present on a message.
+	Tags        []string
+	CreatedFrom string
+	CreatedTo   string
+	After       *MessagePosition
+	Limit       int
+}
+
+// ClaimOptions configure ClaimMessages: up to Limit due messages are leased to Owner for Lease and
+// ranked one priority higher for every PriorityAging they have been due.
+type ClaimOptions struct {
+	Owner         string
+	Lease         time.Duration
+	PriorityAging time.Duration
+	Limit         int
+	// PreserveRecipientOrder leaves out messages of a phone number whose earlier message is waiting
+	// for its retry or still being sent, so they cannot overtake it in a later tick.
+	PreserveRecipientOrder bool
+	// Routes, when set, claims only what each provider accepts.
+	Routes *ClaimRoutes
+}
+
+// ClaimRoutes mirrors the routing of the webhook client: a message goes to the Provider of the first
+// rule it matches, or to Fallback. At most Quotas[provider] messages of a provider are claimed, so
+// none while it accepts none; providers missing from Quotas are unlimited.
+type ClaimRoutes struct {
+	Rules    []RouteRule
+	Fallback string
+	Quotas   map[string]int
+}
+
+// RouteRule matches messages like webhook.Rule, every criterion that is set must match.
+type RouteRule struct {
+	Provider      string
+	PhonePrefixes []string
+	Tags          []string
+	Priorities    []string
+}
+
+// MessagePosition is the sort key of a message in SearchMessages results.
+type MessagePosition struct {
+	CreatedAt string
+	ID        string
+}
+
+// batchInsertSize bounds the number of rows sent in a single INSERT statement by SaveBatch.
+const batchInsertSize = 1000
+
 type PostgresMessagesRepository struct {
 	db *gorm.DB
 }
@@ -28,25 +100,117 @@ func NewMessagesRepository(db *gorm.DB) *PostgresMessagesRepository {
 	return &PostgresMessagesRepository{db: db}
 }
 
-func (r *PostgresMessagesRepository) Save(ctx context.Context, i *entity.MessagesEntity) error {
+func (r *PostgresMessagesRepository) Create(ctx context.Context, i *entity.MessagesEntity) error {
 	message, err := models.MapEntityMessagesToModel(i)
 	if err != nil {
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Save(&message).Error; err != nil {
-		log.Logger.Error().Msgf("Error saving message: %+v", err)
-		return fmt.Errorf("failed to save message with id=%s: %w", message.ID, err)
+	err = insertMessages(r.db.WithContext(ctx)).Create(message).Error
+	if err != nil {
+		log.Logger.Error().Err(err).Str("phone", message.Phone).Msg("Failed to create message")
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	i.Id = message.ID
+	i.CreatedAt = message.CreatedAt
+	i.UpdatedAt = message.UpdatedAt
+	i.Version = message.Version
+
+	log.Logger.Info().Str("messageId", message.ID).Str("status", string(message.Status)).Msg("created message")
+	return nil
+}
+
+func (r *PostgresMessagesRepository) SaveBatch(ctx context.Context, entities []*entity.MessagesEntity) error {
+	messages := make([]*models.Messages, len(entities))
+	for idx, i := range entities {
+		message, err := models.MapEntityMessagesToModel(i)
+		if err != nil {
+			return err
+		}
+		messages[idx] = message
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return insertMessages(tx).CreateInBatches(messages, batchInsertSize).Error
+	})
+	if err != nil {
+		log.Logger.Error().Err(err).Int("count", len(messages)).Msg("Failed to create message batch")
+		return fmt.Errorf("failed to create %d messages: %w", len(messages), err)
 	}
 
-	log.Logger.Info().Str("messageId", message.ID).Str("status", string(message.Status)).Msg("saved message")
+	for idx, message := range messages {
+		entities[idx].Id = message.ID
+		entities[idx].CreatedAt = message.CreatedAt
+		entities[idx].UpdatedAt = message.UpdatedAt
+		entities[idx].Version = message.Version
+	}
+
+	log.Logger.Info().Int("count", len(messages)).Msg("created message batch")
 	return nil
 }
 
+// insertMessages leaves id, timestamps and version to the database defaults and reads them back through RETURNING.
+func insertMessages(db *gorm.DB) *gorm.DB {
+	return db.
+		Omit("id", "created_at", "updated_at", "sent_at", "remote_message_id", "provider", "version").
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}, {Name: "updated_at"}, {Name: "version"}}})
+}
+
+// SaveClaimed stores the outcome of sending a claimed message and ends its lease. It only writes
+// while the message is still SENDING under owner's lease at the version it was claimed with, and
+// reports false otherwise: the lease expired, the reaper released the message and another instance
+// may have sent it since, so its row must not be overwritten. On success the message is refreshed
+// from the updated row.
+func (r *PostgresMessagesRepository) SaveClaimed(ctx context.Context, i *entity.MessagesEntity, owner string) (bool, error) {
+	message, err := models.MapEntityMessagesToModel(i)
+	if err != nil {
+		return false, err
+	}
+
+	var updated models.Messages
+
+	result := r.db.WithContext(ctx).
+		Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND version = ? AND status = ? AND lease_owner = ?", i.Id, i.Version, string(status.SENDING), owner).
+		Updates(map[string]interface{}{
+			"status":            string(message.Status),
+			"content":           message.Content,
+			"attempts":          message.Attempts,
+			"last_error":        message.LastError,
+			"next_attempt_at":   message.NextAttemptAt,
+			"sent_at":           message.SentAt,
+			"remote_message_id": message.RemoteMessageID,
+			"provider":          message.Provider,
+			"template_version":  message.TemplateVersion,
+			"lease_owner":       nil,
+			"lease_expires_at":  nil,
+			"version":           gorm.Expr("version + 1"),
+			"updated_at":        gorm.Expr("now()"),
+		})
+
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Str("messageId", i.Id).Msg("Failed to save claimed message")
+		return false, fmt.Errorf("failed to save message with id=%s: %w", i.Id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	*i = *models.MapModelMessagesToEntity(&updated)
+
+	log.Logger.Info().Str("messageId", i.Id).Str("status", string(i.Status)).Msg("saved message")
+	return true, nil
+}
+
 func (r *PostgresMessagesRepository) GetUnsentMessages(ctx context.Context, recordLimit int) ([]*entity.MessagesEntity, error) {
 	var messages []*models.Messages
 	err := r.db.WithContext(ctx).
-		Where("status = ?", strings.ToLower(string(status.UNSENT))).
+		Where("status IN ?", []string{strings.ToLower(string(status.UNSENT)), strings.ToLower(string(status.RETRYING))}).
+		Where("due_at <= now()").
+		Order("priority_rank, send_at, id").
 		Limit(recordLimit).
 		Find(&messages).Error
 
@@ -62,11 +226,202 @@ func (r *PostgresMessagesRepository) GetUnsentMessages(ctx context.Context, reco
 	return models.MapModelMessagesToEntitySlice(messages), nil
 }
 
+// agedPriorityRank lowers the stored priority_rank by one for every aging interval a message has been
+// due, so a bulk message overtakes newly arriving critical ones after at most three intervals.
+const agedPriorityRank = `priority_rank - floor(extract(epoch FROM now() - due_at) / @aging)`
+
+// claimMessagesQuery moves due messages to SENDING under a lease in one statement. SKIP LOCKED lets
+// concurrent instances claim disjoint rows instead of waiting for each other or sending duplicates.
+// Rows are claimed and returned by aged priority, then send time, which the dispatcher relies on for
+// per-recipient ordering. With @ordered a message is held back while an earlier one of the same
+// phone number is retrying later or being sent.
+//
+// Aging keeps the order of a priority by due_at, so only the first due messages of each priority
+// and provider, read from messages_claim_idx, are candidates and the aged rank is computed for those
+// alone instead of for every waiting message. Each provider reads at most its quota, so a provider
+// that accepts few messages cannot crowd out the others before the quota is applied.
+//
+// {route} names the provider of a message and {routes} lists the providers that accept messages
+// with their quota, see routeClauses.
+const claimMessagesQuery = `
+WITH routes(name, quota) AS (
+	VALUES {routes}
+), candidates AS (
+	SELECT candidate.*, routes.name AS route, routes.quota FROM generate_series(0, 3) AS ranks(value) CROSS JOIN routes, LATERAL (
+		SELECT id, send_at, ` + agedPriorityRank + ` AS claim_rank FROM messages
+		WHERE priority_rank = ranks.value AND due_at <= now() AND status IN @due AND {route} = routes.name
+			AND (NOT @ordered OR NOT EXISTS (
+				SELECT 1 FROM messages earlier
+				WHERE earlier.phone = messages.phone
+					AND (earlier.send_at, earlier.id) < (messages.send_at, messages.id)
+					AND (earlier.status = @sending OR (earlier.status = @retrying AND earlier.next_attempt_at > now()))
+			))
+		ORDER BY due_at, id
+		LIMIT LEAST(routes.quota, @limit)
+	) candidate
+), routed AS (
+	SELECT id, claim_rank, row_number() OVER (PARTITION BY route ORDER BY claim_rank, send_at, id) <= quota AS within_quota
+	FROM candidates
+), picked AS (
+	SELECT messages.id, routed.claim_rank FROM messages JOIN routed USING (id)
+	WHERE messages.status IN @due AND routed.within_quota
+	ORDER BY routed.claim_rank, messages.send_at, messages.id
+	LIMIT @limit
+	FOR UPDATE OF messages SKIP LOCKED
+), claimed AS (
+	UPDATE messages
+	SET status = @sending, lease_owner = @owner, lease_expires_at = now() + make_interval(secs => @lease),
+		version = version + 1, updated_at = now()
+	FROM picked
+	WHERE messages.id = picked.id
+	RETURNING messages.*, picked.claim_rank
+)
+SELECT * FROM claimed ORDER BY claim_rank, send_at, id`
+
+func (r *PostgresMessagesRepository) ClaimMessages(ctx context.Context, claim ClaimOptions) ([]*entity.MessagesEntity, error) {
+	query, params, ok := claimMessagesStatement(claim)
+	if !ok {
+		log.Logger.Info().Str("lease_owner", claim.Owner).Msg("No provider accepts messages, nothing to claim")
+		return nil, nil
+	}
+
+	var messages []*models.Messages
+	err := r.db.WithContext(ctx).
+		Raw(query, params).
+		Scan(&messages).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Str("lease_owner", claim.Owner).Msg("Failed to claim messages")
+		return nil, fmt.Errorf("failed to claim messages: %w", err)
+	}
+
+	log.Logger.Info().Int("claimed_messages", len(messages)).Str("lease_owner", claim.Owner).Msg("Claimed messages for sending")
+
+	return models.MapModelMessagesToEntitySlice(messages), nil
+}
+
+// claimMessagesStatement renders claimMessagesQuery for the claim. It is not ok when no provider
+// accepts messages, there is nothing to claim then.
+func claimMessagesStatement(claim ClaimOptions) (string, map[string]interface{}, bool) {
+	params := map[string]interface{}{
+		"sending":  string(status.SENDING),
+		"retrying": string(status.RETRYING),
+		"owner":    claim.Owner,
+		"lease":    claim.Lease.Seconds(),
+		"due":      []string{string(status.UNSENT), string(status.RETRYING)},
+		"aging":    claim.PriorityAging.Seconds(),
+		"ordered":  claim.PreserveRecipientOrder,
+		"limit":    claim.Limit,
+	}
+	route, routes := routeClauses(claim.Routes, params)
+	if routes == "" {
+		return "", nil, false
+	}
+
+	query := strings.NewReplacer("{route}", route, "{routes}", routes).Replace(claimMessagesQuery)
+	return query, params, true
+}
+
+// routeClauses renders the routing of the claim as SQL, adding its values to params. The route is a
+// CASE over the rules in order, so a message gets the provider of the first rule it matches just like
+// in the router. The routes are VALUES rows of every provider that accepts messages with its quota,
+// empty when none does. Without routes every message shares one unlimited route.
+func routeClauses(routes *ClaimRoutes, params map[string]interface{}) (route string, routeValues string) {
+	if routes == nil {
+		return "''", "('', CAST(@limit AS int))"
+	}
+
+	var routeCase strings.Builder
+	routeCase.WriteString("(CASE")
+	providers := []string{}
+	for i, rule := range routes.Rules {
+		conditions := []string{"true"}
+		if len(rule.PhonePrefixes) > 0 {
+			prefixes := make([]string, len(rule.PhonePrefixes))
+			for j, prefix := range rule.PhonePrefixes {
+				name := fmt.Sprintf("route_%d_prefix_%d", i, j)
+				params[name] = strings.TrimPrefix(prefix, "+")
+				prefixes[j] = fmt.Sprintf("starts_with(ltrim(phone, '+'), @%s)", name)
+			}
+			conditions = append(conditions, "("+strings.Join(prefixes, " OR ")+")")
+		}
+		if len(rule.Tags) > 0 {
+			name := fmt.Sprintf("route_%d_tags", i)
+			params[name] = models.StringArray(rule.Tags)
+			conditions = append(conditions, "tags && @"+name)
+		}
+		if len(rule.Priorities) > 0 {
+			name := fmt.Sprintf("route_%d_priorities", i)
+			params[name] = rule.Priorities
+			conditions = append(conditions, "priority IN @"+name)
+		}
+
+		name := fmt.Sprintf("route_%d_provider", i)
+		params[name] = rule.Provider
+		fmt.Fprintf(&routeCase, " WHEN %s THEN @%s", strings.Join(conditions, " AND "), name)
+		if !slices.Contains(providers, rule.Provider) {
+			providers = append(providers, rule.Provider)
+		}
+	}
+	params["route_fallback"] = routes.Fallback
+	routeCase.WriteString(" ELSE @route_fallback END)")
+	route = routeCase.String()
+	if len(routes.Rules) == 0 {
+		route = "@route_fallback"
+	}
+	if !slices.Contains(providers, routes.Fallback) {
+		providers = append(providers, routes.Fallback)
+	}
+
+	// untyped parameters would make the columns text, the casts keep them comparable
+	var rows []string
+	for i, provider := range providers {
+		quota, limited := routes.Quotas[provider]
+		if limited && quota <= 0 {
+			continue
+		}
+		params[fmt.Sprintf("open_route_%d", i)] = provider
+		if limited {
+			params[fmt.Sprintf("open_route_%d_quota", i)] = quota
+			rows = append(rows, fmt.Sprintf("(CAST(@open_route_%d AS text), CAST(@open_route_%d_quota AS int))", i, i))
+		} else {
+			rows = append(rows, fmt.Sprintf("(CAST(@open_route_%d AS text), CAST(@limit AS int))", i))
+		}
+	}
+	return route, strings.Join(rows, ", ")
+}
+
+// ReleaseExpiredLeases returns messages whose claiming instance died before finishing them to UNSENT.
+func (r *PostgresMessagesRepository) ReleaseExpiredLeases(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Messages{}).
+		Where("status = ? AND lease_expires_at < now()", string(status.SENDING)).
+		Updates(map[string]interface{}{
+			"status":           string(status.UNSENT),
+			"lease_owner":      nil,
+			"lease_expires_at": nil,
+			"version":          gorm.Expr("version + 1"),
+			"updated_at":       gorm.Expr("now()"),
+		})
+
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Msg("Failed to release expired leases")
+		return 0, fmt.Errorf("failed to release expired leases: %w", result.Error)
+	}
+
+	if result.RowsAffected > 0 {
+		log.Logger.Warn().Int64("released_messages", result.RowsAffected).Msg("Released messages with expired leases")
+	}
+
+	return result.RowsAffected, nil
+}
+
 func (r *PostgresMessagesRepository) GetSentMessages(ctx context.Context, recordLimit int) ([]*entity.MessagesEntity, error) {
 	var messages []*models.Messages
 
 	err := r.db.WithContext(ctx).
-		Where("status = ?", strings.ToLower(string(status.SENT))).
+		// delivery receipts move sent messages on, they still count as sent
+		Where("status IN ?", []string{string(status.SENT), string(status.DELIVERED), string(status.UNDELIVERED)}).
 		Order("sent_at DESC").
 		Limit(recordLimit).
 		Find(&messages).Error
@@ -82,3 +437,175 @@ func (r *PostgresMessagesRepository) GetSentMessages(ctx context.Context, record
 
 	return models.MapModelMessagesToEntitySlice(messages), nil
 }
+
+func (r *PostgresMessagesRepository) GetMessage(ctx context.Context, id string) (*entity.MessagesEntity, error) {
+	// ids are serial numbers, anything else can not match and would only make postgres reject the query
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return nil, ErrMessageNotFound
+	}
+
+	var message models.Messages
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Str("messageId", id).Msg("Failed to fetch message from database")
+		return nil, fmt.Errorf("failed to fetch message with id=%s: %w", id, err)
+	}
+
+	return models.MapModelMessagesToEntity(&message), nil
+}
+
+// UpdatePending writes the editable fields and status of a message, but only while it is still waiting
+// to be sent and nobody changed it since it was read, i.e. its version still matches. It reports false
+// when either condition no longer holds; on success the message is refreshed from the updated row.
+func (r *PostgresMessagesRepository) UpdatePending(ctx context.Context, i *entity.MessagesEntity) (bool, error) {
+	message, err := models.MapEntityMessagesToModel(i)
+	if err != nil {
+		return false, err
+	}
+
+	var updated models.Messages
+
+	result := r.db.WithContext(ctx).
+		Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND version = ? AND status IN ?", i.Id, i.Version, []string{string(status.UNSENT), string(status.RETRYING)}).
+		Updates(map[string]interface{}{
+			"content":          i.Content,
+			"priority":         string(i.Priority),
+			"send_at":          i.SendAt,
+			"status":           string(i.Status),
+			"template_id":      message.TemplateID,
+			"template_version": message.TemplateVersion,
+			"variables":        message.Variables,
+			"version":          gorm.Expr("version + 1"),
+			"updated_at":       gorm.Expr("now()"),
+		})
+
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Str("messageId", i.Id).Msg("Failed to update pending message")
+		return false, fmt.Errorf("failed to update message with id=%s: %w", i.Id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	*i = *models.MapModelMessagesToEntity(&updated)
+
+	log.Logger.Info().Str("messageId", i.Id).Str("status", string(i.Status)).Int("version", i.Version).Msg("updated pending message")
+	return true, nil
+}
+
+// GetMessageByRemoteID finds a message by the id the provider gave it, ids of different providers may collide.
+func (r *PostgresMessagesRepository) GetMessageByRemoteID(ctx context.Context, provider string, remoteMessageID string) (*entity.MessagesEntity, error) {
+	var message models.Messages
+
+	err := r.db.WithContext(ctx).Where("provider = ? AND remote_message_id = ?", provider, remoteMessageID).Take(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Str("provider", provider).Str("remoteMessageId", remoteMessageID).Msg("Failed to fetch message by remote id from database")
+		return nil, fmt.Errorf("failed to fetch message with remote id=%s: %w", remoteMessageID, err)
+	}
+
+	return models.MapModelMessagesToEntity(&message), nil
+}
+
+// RecordDelivery writes the delivery status, timestamp and error of a sent message unless its version
+// changed since it was read, in which case it reports false. On success the message is refreshed from
+// the updated row.
+func (r *PostgresMessagesRepository) RecordDelivery(ctx context.Context, i *entity.MessagesEntity) (bool, error) {
+	var updated models.Messages
+
+	result := r.db.WithContext(ctx).
+		Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND version = ?", i.Id, i.Version).
+		Updates(map[string]interface{}{
+			"status":               string(i.Status),
+			"delivery_reported_at": i.DeliveryReportedAt,
+			"delivery_error":       i.DeliveryError,
+			"version":              gorm.Expr("version + 1"),
+			"updated_at":           gorm.Expr("now()"),
+		})
+
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Str("messageId", i.Id).Msg("Failed to record message delivery")
+		return false, fmt.Errorf("failed to record delivery of message with id=%s: %w", i.Id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	*i = *models.MapModelMessagesToEntity(&updated)
+
+	log.Logger.Info().Str("messageId", i.Id).Str("status", string(i.Status)).Msg("recorded message delivery")
+	return true, nil
+}
+
+func (r *PostgresMessagesRepository) GetMessageHistory(ctx context.Context, id string) ([]*entity.MessageHistoryEntity, error) {
+	var history []*models.MessageHistory
+
+	err := r.db.WithContext(ctx).
+		Where("message_id = ?", id).
+		Order("id").
+		Find(&history).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Str("messageId", id).Msg("Failed to fetch message history from database")
+		return nil, fmt.Errorf("failed to fetch history of message with id=%s: %w", id, err)
+	}
+
+	return models.MapModelMessageHistoryToEntitySlice(history), nil
+}
+
+func (r *PostgresMessagesRepository) SearchMessages(ctx context.Context, filter MessageFilter) ([]*entity.MessagesEntity, error) {
+	query := r.db.WithContext(ctx).Model(&models.Messages{})
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, messageStatus := range filter.Statuses {
+			statuses[i] = string(messageStatus)
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+	if filter.Phone != "" {
+		query = query.Where("phone = ?", filter.Phone)
+	}
+	if filter.RemoteMessageID != "" {
+		query = query.Where("remote_message_id = ?", filter.RemoteMessageID)
+	}
+	if len(filter.Tags) > 0 {
+		query = query.Where("tags @> ?", models.StringArray(filter.Tags))
+	}
+	if filter.CreatedFrom != "" {
+		query = query.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if filter.CreatedTo != "" {
+		query = query.Where("created_at < ?", filter.CreatedTo)
+	}
+	if filter.After != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	var messages []*models.Messages
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Find(&messages).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to search messages in database")
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	log.Logger.Info().Int("found_messages", len(messages)).Msg("Searched messages in database")
+
+	return models.MapModelMessagesToEntitySlice(messages), nil
+}
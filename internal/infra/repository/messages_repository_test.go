@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// renderClaim binds the claim statement like postgres would receive it, without a database.
+func renderClaim(t *testing.T, claim ClaimOptions) (string, []interface{}) {
+	t.Helper()
+
+	query, params, ok := claimMessagesStatement(claim)
+	if !ok {
+		t.Fatal("claim has no open route")
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement := db.Raw(query, params).Statement
+	return statement.SQL.String(), statement.Vars
+}
+
+func claimWithRoutes(routes *ClaimRoutes) ClaimOptions {
+	return ClaimOptions{Owner: "instance-1", Lease: time.Minute, PriorityAging: time.Minute, Limit: 10, Routes: routes}
+}
+
+func TestClaimMessagesStatement_WithoutRoutes(t *testing.T) {
+	sql, _ := renderClaim(t, claimWithRoutes(nil))
+
+	assert.Contains(t, sql, "VALUES ('', CAST($")
+	assert.NotContains(t, sql, "@")
+}
+
+func TestClaimMessagesStatement_WithoutQuotas(t *testing.T) {
+	query, params, ok := claimMessagesStatement(claimWithRoutes(&ClaimRoutes{
+		Rules:    []RouteRule{{Provider: "international", PhonePrefixes: []string{"+49"}}},
+		Fallback: "default",
+	}))
+
+	assert.True(t, ok)
+	assert.Contains(t, query, "VALUES (CAST(@open_route_0 AS text), CAST(@limit AS int)), (CAST(@open_route_1 AS text), CAST(@limit AS int))")
+	assert.Equal(t, "international", params["open_route_0"])
+	assert.Equal(t, "default", params["open_route_1"])
+
+	sql, _ := renderClaim(t, claimWithRoutes(&ClaimRoutes{Fallback: "default"}))
+	assert.NotContains(t, sql, "CASE route")
+	assert.NotContains(t, sql, "@")
+}
+
+func TestClaimMessagesStatement_OnlyClosedQuotas(t *testing.T) {
+	_, _, ok := claimMessagesStatement(claimWithRoutes(&ClaimRoutes{
+		Rules:    []RouteRule{{Provider: "international", PhonePrefixes: []string{"+49"}}},
+		Fallback: "default",
+		Quotas:   map[string]int{"international": 0, "default": 0},
+	}))
+
+	assert.False(t, ok)
+
+	query, params, ok := claimMessagesStatement(claimWithRoutes(&ClaimRoutes{
+		Rules:    []RouteRule{{Provider: "international", PhonePrefixes: []string{"+49"}}},
+		Fallback: "default",
+		Quotas:   map[string]int{"international": 0},
+	}))
+
+	assert.True(t, ok)
+	assert.Contains(t, query, "VALUES (CAST(@open_route_1 AS text), CAST(@limit AS int))\n")
+	assert.NotContains(t, params, "open_route_0")
+}
+
+func TestClaimMessagesStatement_MixedQuotas(t *testing.T) {
+	routes := &ClaimRoutes{
+		Rules: []RouteRule{
+			{Provider: "international", PhonePrefixes: []string{"+49", "+44"}, Tags: []string{"otp"}},
+			{Provider: "backup", Priorities: []string{"bulk"}},
+		},
+		Fallback: "default",
+		Quotas:   map[string]int{"international": 1, "backup": 0},
+	}
+
+	query, params, ok := claimMessagesStatement(claimWithRoutes(routes))
+
+	assert.True(t, ok)
+	assert.Contains(t, query, "VALUES (CAST(@open_route_0 AS text), CAST(@open_route_0_quota AS int)), (CAST(@open_route_2 AS text), CAST(@limit AS int))\n")
+	assert.Equal(t, "international", params["open_route_0"])
+	assert.Equal(t, 1, params["open_route_0_quota"])
+	assert.Equal(t, "default", params["open_route_2"])
+	assert.NotContains(t, params, "open_route_1")
+
+	sql, _ := renderClaim(t, claimWithRoutes(routes))
+	assert.NotContains(t, sql, "@")
+}
+
+// A provider that accepts a single message must not use up the candidates of the others: every
+// route reads its own candidates, limited by its own quota, before the batch is cut to the limit.
+func TestClaimMessagesStatement_QuotaLimitsCandidatesPerRoute(t *testing.T) {
+	query, params, ok := claimMessagesStatement(claimWithRoutes(&ClaimRoutes{
+		Rules:    []RouteRule{{Provider: "international", PhonePrefixes: []string{"+49"}}},
+		Fallback: "default",
+		Quotas:   map[string]int{"international": 1},
+	}))
+	assert.True(t, ok)
+	assert.Equal(t, 1, params["open_route_0_quota"])
+	assert.Contains(t, query, "(CAST(@open_route_1 AS text), CAST(@limit AS int))")
+
+	candidates := query[strings.Index(query, "candidates AS"):strings.Index(query, "routed AS")]
+	assert.Contains(t, candidates, "CROSS JOIN routes, LATERAL")
+	assert.Contains(t, candidates, "= routes.name")
+	assert.Contains(t, candidates, "LIMIT LEAST(routes.quota, @limit)")
+	assert.NotContains(t, candidates, "LIMIT @limit")
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/domain/types/status"
+)
+
+// MessageHistory rows are written by the messages_history trigger in local/init.sql, never by the application.
+type MessageHistory struct {
+	ID              string                   `gorm:"primaryKey;column:id"`
+	MessageID       string                   `gorm:"column:message_id"`
+	Status          status.MessageStatus     `gorm:"column:status"`
+	Priority        priority.MessagePriority `gorm:"column:priority"`
+	Content         string                   `gorm:"column:content"`
+	SendAt          string                   `gorm:"column:send_at"`
+	Attempts        int                      `gorm:"column:attempts"`
+	LastError       string                   `gorm:"column:last_error"`
+	RemoteMessageID string                   `gorm:"column:remote_message_id"`
+	Version         int                      `gorm:"column:version"`
+	RecordedAt      string                   `gorm:"column:recorded_at"`
+}
+
+func (MessageHistory) TableName() string {
+	return "message_history"
+}
+
+func MapModelMessageHistoryToEntitySlice(history []*MessageHistory) []*entity.MessageHistoryEntity {
+	entities := make([]*entity.MessageHistoryEntity, len(history))
+	for i, h := range history {
+		entities[i] = &entity.MessageHistoryEntity{
+			Id:              h.ID,
+			MessageId:       h.MessageID,
+			Status:          h.Status,
+			Priority:        h.Priority,
+			Content:         h.Content,
+			SendAt:          h.SendAt,
+			Attempts:        h.Attempts,
+			LastError:       h.LastError,
+			RemoteMessageId: h.RemoteMessageID,
+			Version:         h.Version,
+			RecordedAt:      h.RecordedAt,
+		}
+	}
+	return entities
+}
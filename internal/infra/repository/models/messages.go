@@ -2,18 +2,35 @@ package models
 
 import (
 	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
 	"message-scheduler/internal/domain/types/status"
 )
 
 type Messages struct {
-	ID              string               `gorm:"primaryKey;column:id"`
-	Phone           string               `gorm:"phone"`
-	Content         string               `gorm:"content"`
-	Status          status.MessageStatus `gorm:"type:varchar(100);not null"`
-	CreatedAt       string               `gorm:"created_at"`
-	UpdatedAt       string               `gorm:"updated_at"`
-	SentAt          string               `gorm:"sent_at"`
-	RemoteMessageID string               `gorm:"remote_message_id"`
+	ID                 string                   `gorm:"primaryKey;column:id"`
+	Phone              string                   `gorm:"phone"`
+	Content            string                   `gorm:"content"`
+	Status             status.MessageStatus     `gorm:"type:varchar(100);not null"`
+	Priority           priority.MessagePriority `gorm:"column:priority;type:varchar(20);not null"`
+	Tags               StringArray              `gorm:"column:tags;type:text[]"`
+	CreatedAt          string                   `gorm:"created_at"`
+	UpdatedAt          string                   `gorm:"updated_at"`
+	SendAt             string                   `gorm:"column:send_at"`
+	SentAt             *string                  `gorm:"sent_at"`
+	RemoteMessageID    string                   `gorm:"remote_message_id"`
+	Provider           string                   `gorm:"column:provider"`
+	Attempts           int                      `gorm:"column:attempts"`
+	LastError          string                   `gorm:"column:last_error"`
+	NextAttemptAt      *string                  `gorm:"column:next_attempt_at"`
+	LeaseOwner         *string                  `gorm:"column:lease_owner"`
+	LeaseExpiresAt     *string                  `gorm:"column:lease_expires_at"`
+	DeliveryReportedAt *string                  `gorm:"column:delivery_reported_at"`
+	DeliveryError      string                   `gorm:"column:delivery_error"`
+	Version            int                      `gorm:"column:version"`
+	TemplateID         *string                  `gorm:"column:template_id"`
+	TemplateVersion    *int                     `gorm:"column:template_version"`
+	Variables          StringMap                `gorm:"column:variables;type:jsonb"`
+	Locale             *string                  `gorm:"column:locale"`
 }
 
 func (Messages) TableName() string {
@@ -23,28 +40,60 @@ func (Messages) TableName() string {
 func MapEntityMessagesToModel(i *entity.MessagesEntity) (*Messages, error) {
 
 	return &Messages{
-		ID:              i.Id,
-		Phone:           i.Phone,
-		Content:         i.Content,
-		Status:          i.Status,
-		CreatedAt:       i.CreatedAt,
-		UpdatedAt:       i.UpdatedAt,
-		SentAt:          i.SentAt,
-		RemoteMessageID: i.RemoteMessageId,
+		ID:                 i.Id,
+		Phone:              i.Phone,
+		Content:            i.Content,
+		Status:             i.Status,
+		Priority:           i.Priority,
+		Tags:               StringArray(i.Tags),
+		CreatedAt:          i.CreatedAt,
+		UpdatedAt:          i.UpdatedAt,
+		SendAt:             i.SendAt,
+		SentAt:             nullableString(i.SentAt),
+		RemoteMessageID:    i.RemoteMessageId,
+		Provider:           i.Provider,
+		Attempts:           i.Attempts,
+		LastError:          i.LastError,
+		NextAttemptAt:      nullableString(i.NextAttemptAt),
+		LeaseOwner:         nullableString(i.LeaseOwner),
+		LeaseExpiresAt:     nullableString(i.LeaseExpiresAt),
+		DeliveryReportedAt: nullableString(i.DeliveryReportedAt),
+		DeliveryError:      i.DeliveryError,
+		Version:            i.Version,
+		TemplateID:         nullableString(i.TemplateId),
+		TemplateVersion:    nullableInt(i.TemplateVersion),
+		Variables:          StringMap(i.Variables),
+		Locale:             nullableString(i.Locale),
 	}, nil
 }
 
 func MapModelMessagesToEntity(i *Messages) *entity.MessagesEntity {
 
 	return &entity.MessagesEntity{
-		Id:              i.ID,
-		Phone:           i.Phone,
-		Content:         i.Content,
-		Status:          i.Status,
-		CreatedAt:       i.CreatedAt,
-		UpdatedAt:       i.UpdatedAt,
-		SentAt:          i.SentAt,
-		RemoteMessageId: i.RemoteMessageID,
+		Id:                 i.ID,
+		Phone:              i.Phone,
+		Content:            i.Content,
+		Status:             i.Status,
+		Priority:           i.Priority,
+		Tags:               []string(i.Tags),
+		CreatedAt:          i.CreatedAt,
+		UpdatedAt:          i.UpdatedAt,
+		SendAt:             i.SendAt,
+		SentAt:             stringValue(i.SentAt),
+		RemoteMessageId:    i.RemoteMessageID,
+		Provider:           i.Provider,
+		Attempts:           i.Attempts,
+		LastError:          i.LastError,
+		NextAttemptAt:      stringValue(i.NextAttemptAt),
+		LeaseOwner:         stringValue(i.LeaseOwner),
+		LeaseExpiresAt:     stringValue(i.LeaseExpiresAt),
+		DeliveryReportedAt: stringValue(i.DeliveryReportedAt),
+		DeliveryError:      i.DeliveryError,
+		Version:            i.Version,
+		TemplateId:         stringValue(i.TemplateID),
+		TemplateVersion:    intValue(i.TemplateVersion),
+		Variables:          map[string]string(i.Variables),
+		Locale:             stringValue(i.Locale),
 	}
 }
 
@@ -55,3 +104,32 @@ func MapModelMessagesToEntitySlice(messages []*Messages) []*entity.MessagesEntit
 	}
 	return entities
 }
+
+// nullableString maps the entity's empty values to SQL NULL, postgres rejects empty strings for TIMESTAMP columns.
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func nullableInt(value int) *int {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/event"
+)
+
+// OutboxEvents rows are written by the messages_outbox trigger in local/init.sql, the application only
+// claims and publishes them.
+type OutboxEvents struct {
+	ID             string          `gorm:"primaryKey;column:id"`
+	EventType      event.EventType `gorm:"column:event_type"`
+	MessageID      string          `gorm:"column:message_id"`
+	Payload        string          `gorm:"column:payload"`
+	CreatedAt      string          `gorm:"column:created_at"`
+	Attempts       int             `gorm:"column:attempts"`
+	LastError      *string         `gorm:"column:last_error"`
+	LeaseOwner     *string         `gorm:"column:lease_owner"`
+	LeaseExpiresAt *string         `gorm:"column:lease_expires_at"`
+	PublishedAt    *string         `gorm:"column:published_at"`
+}
+
+func (OutboxEvents) TableName() string {
+	return "outbox_events"
+}
+
+func MapModelOutboxEventsToEntitySlice(events []*OutboxEvents) []*entity.OutboxEventEntity {
+	entities := make([]*entity.OutboxEventEntity, len(events))
+	for i, e := range events {
+		entities[i] = &entity.OutboxEventEntity{
+			Id:        e.ID,
+			Type:      e.EventType,
+			MessageId: e.MessageID,
+			Payload:   json.RawMessage(e.Payload),
+			CreatedAt: e.CreatedAt,
+			Attempts:  e.Attempts,
+			LastError: stringValue(e.LastError),
+		}
+	}
+	return entities
+}
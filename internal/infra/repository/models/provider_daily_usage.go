@@ -0,0 +1,11 @@
+package models
+
+type ProviderDailyUsage struct {
+	Provider string `gorm:"primaryKey;column:provider"`
+	Day      string `gorm:"primaryKey;column:day"`
+	Sent     int    `gorm:"column:sent"`
+}
+
+func (ProviderDailyUsage) TableName() string {
+	return "provider_daily_usage"
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"message-scheduler/internal/domain/entity"
+	"time"
+)
+
+// schedulerSettingsID is the key of the single scheduler_settings row.
+const schedulerSettingsID = 1
+
+type SchedulerSettings struct {
+	ID                     int    `gorm:"primaryKey;column:id"`
+	BatchSize              int    `gorm:"column:batch_size"`
+	IntervalMs             int64  `gorm:"column:interval_ms"`
+	JobTimeoutMs           int64  `gorm:"column:job_timeout_ms"`
+	Concurrency            int    `gorm:"column:concurrency"`
+	PreserveRecipientOrder bool   `gorm:"column:preserve_recipient_order"`
+	PriorityAgingMs        int64  `gorm:"column:priority_aging_ms"`
+	UpdatedAt              string `gorm:"column:updated_at"`
+}
+
+func (SchedulerSettings) TableName() string {
+	return "scheduler_settings"
+}
+
+func MapEntitySchedulerSettingsToModel(i *entity.SchedulerSettingsEntity) *SchedulerSettings {
+	return &SchedulerSettings{
+		ID:                     schedulerSettingsID,
+		BatchSize:              i.BatchSize,
+		IntervalMs:             i.Interval.Milliseconds(),
+		JobTimeoutMs:           i.JobTimeout.Milliseconds(),
+		Concurrency:            i.Concurrency,
+		PreserveRecipientOrder: i.PreserveRecipientOrder,
+		PriorityAgingMs:        i.PriorityAging.Milliseconds(),
+	}
+}
+
+func MapModelSchedulerSettingsToEntity(i *SchedulerSettings) *entity.SchedulerSettingsEntity {
+	return &entity.SchedulerSettingsEntity{
+		BatchSize:              i.BatchSize,
+		Interval:               time.Duration(i.IntervalMs) * time.Millisecond,
+		JobTimeout:             time.Duration(i.JobTimeoutMs) * time.Millisecond,
+		Concurrency:            i.Concurrency,
+		PreserveRecipientOrder: i.PreserveRecipientOrder,
+		PriorityAging:          time.Duration(i.PriorityAgingMs) * time.Millisecond,
+		UpdatedAt:              i.UpdatedAt,
+	}
+}
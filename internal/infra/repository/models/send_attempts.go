@@ -0,0 +1,53 @@
+package models
+
+import (
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/attempt"
+)
+
+type SendAttempts struct {
+	ID              string                    `gorm:"primaryKey;column:id"`
+	MessageID       string                    `gorm:"column:message_id"`
+	Attempt         int                       `gorm:"column:attempt"`
+	IdempotencyKey  string                    `gorm:"column:idempotency_key"`
+	Status          attempt.SendAttemptStatus `gorm:"column:status"`
+	RemoteMessageID *string                   `gorm:"column:remote_message_id"`
+	Provider        *string                   `gorm:"column:provider"`
+	Error           *string                   `gorm:"column:error"`
+	StartedAt       string                    `gorm:"column:started_at"`
+	FinishedAt      *string                   `gorm:"column:finished_at"`
+}
+
+func (SendAttempts) TableName() string {
+	return "send_attempts"
+}
+
+func MapEntitySendAttemptToModel(i *entity.SendAttemptEntity) *SendAttempts {
+	return &SendAttempts{
+		ID:              i.Id,
+		MessageID:       i.MessageId,
+		Attempt:         i.Attempt,
+		IdempotencyKey:  i.IdempotencyKey,
+		Status:          i.Status,
+		RemoteMessageID: nullableString(i.RemoteMessageId),
+		Provider:        nullableString(i.Provider),
+		Error:           nullableString(i.Error),
+		StartedAt:       i.StartedAt,
+		FinishedAt:      nullableString(i.FinishedAt),
+	}
+}
+
+func MapModelSendAttemptToEntity(i *SendAttempts) *entity.SendAttemptEntity {
+	return &entity.SendAttemptEntity{
+		Id:              i.ID,
+		MessageId:       i.MessageID,
+		Attempt:         i.Attempt,
+		IdempotencyKey:  i.IdempotencyKey,
+		Status:          i.Status,
+		RemoteMessageId: stringValue(i.RemoteMessageID),
+		Provider:        stringValue(i.Provider),
+		Error:           stringValue(i.Error),
+		StartedAt:       i.StartedAt,
+		FinishedAt:      stringValue(i.FinishedAt),
+	}
+}
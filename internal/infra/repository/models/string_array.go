@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// StringArray maps a postgres TEXT[] column. The driver hands arrays over in their text form, e.g.
+// {otp,"with space"}, so values are encoded and decoded here.
+type StringArray []string
+
+func (a StringArray) Value() (driver.Value, error) {
+	elements := make([]string, len(a))
+	for i, element := range a {
+		elements[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(element) + `"`
+	}
+	return "{" + strings.Join(elements, ",") + "}", nil
+}
+
+func (a *StringArray) Scan(src interface{}) error {
+	var literal string
+	switch value := src.(type) {
+	case nil:
+		*a = StringArray{}
+		return nil
+	case string:
+		literal = value
+	case []byte:
+		literal = string(value)
+	default:
+		return fmt.Errorf("cannot scan %T into StringArray", src)
+	}
+
+	elements, err := parseArrayLiteral(literal)
+	if err != nil {
+		return err
+	}
+	*a = elements
+	return nil
+}
+
+// parseArrayLiteral reads a one-dimensional array literal. NULL elements are not expected in tag
+// columns and are read as the string "NULL".
+func parseArrayLiteral(literal string) (StringArray, error) {
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return nil, fmt.Errorf("invalid array literal %q", literal)
+	}
+
+	body := literal[1 : len(literal)-1]
+	elements := StringArray{}
+	if body == "" {
+		return elements, nil
+	}
+
+	var (
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+	for _, r := range body {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			elements = append(elements, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted || escaped {
+		return nil, fmt.Errorf("invalid array literal %q", literal)
+	}
+
+	return append(elements, current.String()), nil
+}
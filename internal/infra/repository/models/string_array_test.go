@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringArray_RoundTrip(t *testing.T) {
+	tags := StringArray{"otp", "with space", `quo"te`, `back\slash`, "a,b"}
+
+	value, err := tags.Value()
+	assert.NoError(t, err)
+
+	var scanned StringArray
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, tags, scanned)
+}
+
+func TestStringArray_ScanPostgresLiterals(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    StringArray
+		wantErr bool
+	}{
+		{name: "null", src: nil, want: StringArray{}},
+		{name: "empty", src: "{}", want: StringArray{}},
+		{name: "unquoted", src: []byte("{otp,campaign-42}"), want: StringArray{"otp", "campaign-42"}},
+		{name: "quoted", src: `{"with space",otp}`, want: StringArray{"with space", "otp"}},
+		{name: "not an array", src: "otp", wantErr: true},
+		{name: "unterminated quote", src: `{"otp}`, wantErr: true},
+		{name: "unsupported type", src: 42, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scanned StringArray
+			err := scanned.Scan(tt.src)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, scanned)
+		})
+	}
+}
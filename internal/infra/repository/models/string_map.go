@@ -0,0 +1,44 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringMap maps a nullable postgres JSONB column holding an object of strings. A nil map is
+// stored as NULL.
+type StringMap map[string]string
+
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(map[string]string(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func (m *StringMap) Scan(src interface{}) error {
+	var document []byte
+	switch value := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case string:
+		document = []byte(value)
+	case []byte:
+		document = value
+	default:
+		return fmt.Errorf("cannot scan %T into StringMap", src)
+	}
+
+	var decoded map[string]string
+	if err := json.Unmarshal(document, &decoded); err != nil {
+		return fmt.Errorf("invalid string map %q: %w", document, err)
+	}
+	*m = decoded
+	return nil
+}
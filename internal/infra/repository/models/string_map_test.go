@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringMap_RoundTrip(t *testing.T) {
+	variables := StringMap{"name": "Ayşe", "code": `"4711"`}
+
+	value, err := variables.Value()
+	assert.NoError(t, err)
+
+	var scanned StringMap
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, variables, scanned)
+}
+
+func TestStringMap_Null(t *testing.T) {
+	value, err := StringMap(nil).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	scanned := StringMap{"stale": "value"}
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.Error(t, scanned.Scan([]byte(`["not","an","object"]`)))
+	assert.Error(t, scanned.Scan(42))
+}
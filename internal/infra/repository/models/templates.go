@@ -0,0 +1,76 @@
+package models
+
+import "message-scheduler/internal/domain/entity"
+
+type Templates struct {
+	ID        string `gorm:"primaryKey;column:id"`
+	Name      string `gorm:"column:name"`
+	Version   int    `gorm:"column:version"`
+	CreatedAt string `gorm:"column:created_at"`
+	UpdatedAt string `gorm:"column:updated_at"`
+}
+
+func (Templates) TableName() string {
+	return "templates"
+}
+
+type TemplateVersions struct {
+	ID         string      `gorm:"primaryKey;column:id"`
+	TemplateID string      `gorm:"column:template_id"`
+	Version    int         `gorm:"column:version"`
+	Content    string      `gorm:"column:content"`
+	Locale     *string     `gorm:"column:locale"`
+	Variants   StringMap   `gorm:"column:variants;type:jsonb"`
+	Variables  StringArray `gorm:"column:variables;type:text[]"`
+	CreatedAt  string      `gorm:"column:created_at"`
+}
+
+func (TemplateVersions) TableName() string {
+	return "template_versions"
+}
+
+func MapEntityTemplateToVersionModel(i *entity.TemplateEntity, templateID string, version int) *TemplateVersions {
+	return &TemplateVersions{
+		TemplateID: templateID,
+		Version:    version,
+		Content:    i.Content,
+		Locale:     nullableString(i.Locale),
+		Variants:   StringMap(i.Variants),
+		Variables:  StringArray(i.Variables),
+	}
+}
+
+// TemplateVersionRow is a template version joined with the name and creation time of its template.
+type TemplateVersionRow struct {
+	TemplateID       string
+	Name             string
+	Version          int
+	Content          string
+	Locale           *string
+	Variants         StringMap
+	Variables        StringArray
+	CreatedAt        string
+	VersionCreatedAt string
+}
+
+func MapTemplateVersionRowToEntity(i *TemplateVersionRow) *entity.TemplateEntity {
+	return &entity.TemplateEntity{
+		Id:        i.TemplateID,
+		Name:      i.Name,
+		Version:   i.Version,
+		Content:   i.Content,
+		Locale:    stringValue(i.Locale),
+		Variants:  map[string]string(i.Variants),
+		Variables: []string(i.Variables),
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.VersionCreatedAt,
+	}
+}
+
+func MapTemplateVersionRowsToEntitySlice(rows []*TemplateVersionRow) []*entity.TemplateEntity {
+	entities := make([]*entity.TemplateEntity, len(rows))
+	for i, row := range rows {
+		entities[i] = MapTemplateVersionRowToEntity(row)
+	}
+	return entities
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/infra/repository/models"
+	"message-scheduler/log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	ClaimEvents(ctx context.Context, owner string, lease time.Duration, recordLimit int) ([]*entity.OutboxEventEntity, error)
+	MarkPublished(ctx context.Context, ids []string) error
+	ReleaseEvents(ctx context.Context, ids []string, retryAfter time.Duration, publishErr string) error
+	DeletePublished(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type PostgresOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *PostgresOutboxRepository {
+	db.Logger = &GormLogger{log.Logger}
+
+	return &PostgresOutboxRepository{db: db}
+}
+
+// claimEventsQuery leases the oldest unpublished events to one relay. lease_expires_at doubles as the
+// retry time of events whose publishing failed, so both wait until it has passed. Only the oldest
+// unpublished event of a message is claimed: its later events wait until it is published, so
+// neither a failed batch nor a concurrent relay lets them overtake it.
+const claimEventsQuery = `
+WITH claimed AS (
+	UPDATE outbox_events
+	SET lease_owner = @owner, lease_expires_at = now() + make_interval(secs => @lease)
+	WHERE id IN (
+		SELECT id FROM outbox_events
+		WHERE published_at IS NULL AND (lease_expires_at IS NULL OR lease_expires_at < now())
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events earlier
+				WHERE earlier.message_id = outbox_events.message_id AND earlier.id < outbox_events.id
+					AND earlier.published_at IS NULL
+			)
+		ORDER BY id
+		LIMIT @limit
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+)
+SELECT * FROM claimed ORDER BY id`
+
+func (r *PostgresOutboxRepository) ClaimEvents(ctx context.Context, owner string, lease time.Duration, recordLimit int) ([]*entity.OutboxEventEntity, error) {
+	var events []*models.OutboxEvents
+
+	err := r.db.WithContext(ctx).
+		Raw(claimEventsQuery, map[string]interface{}{
+			"owner": owner,
+			"lease": lease.Seconds(),
+			"limit": recordLimit,
+		}).
+		Scan(&events).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Str("lease_owner", owner).Msg("Failed to claim outbox events")
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	log.Logger.Debug().Int("claimed_events", len(events)).Str("lease_owner", owner).Msg("Claimed outbox events")
+
+	return models.MapModelOutboxEventsToEntitySlice(events), nil
+}
+
+func (r *PostgresOutboxRepository) MarkPublished(ctx context.Context, ids []string) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.OutboxEvents{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"published_at":     gorm.Expr("now()"),
+			"lease_owner":      nil,
+			"lease_expires_at": nil,
+		}).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Strs("event_ids", ids).Msg("Failed to mark outbox events published")
+		return fmt.Errorf("failed to mark outbox events published: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseEvents gives up the lease on events that could not be published and holds them back for retryAfter.
+func (r *PostgresOutboxRepository) ReleaseEvents(ctx context.Context, ids []string, retryAfter time.Duration, publishErr string) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.OutboxEvents{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_error":       publishErr,
+			"lease_owner":      nil,
+			"lease_expires_at": gorm.Expr("now() + make_interval(secs => ?)", retryAfter.Seconds()),
+		}).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Strs("event_ids", ids).Msg("Failed to release outbox events")
+		return fmt.Errorf("failed to release outbox events: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePublished removes events published more than olderThan ago.
+func (r *PostgresOutboxRepository) DeletePublished(ctx context.Context, olderThan time.Duration) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at < now() - make_interval(secs => ?)", olderThan.Seconds()).
+		Delete(&models.OutboxEvents{})
+
+	if result.Error != nil {
+		log.Logger.Error().Err(result.Error).Msg("Failed to delete published outbox events")
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"message-scheduler/internal/infra/repository/models"
+	"message-scheduler/log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProviderUsageRepository counts the requests made to every provider per UTC day, so all instances
+// share one daily cap.
+type ProviderUsageRepository interface {
+	Reserve(ctx context.Context, provider string, day time.Time, limit int) (bool, error)
+	Release(ctx context.Context, provider string, day time.Time) error
+	Count(ctx context.Context, provider string, day time.Time) (int, error)
+}
+
+type PostgresProviderUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewProviderUsageRepository(db *gorm.DB) *PostgresProviderUsageRepository {
+	db.Logger = &GormLogger{log.Logger}
+
+	return &PostgresProviderUsageRepository{db: db}
+}
+
+// reserveUsageQuery counts one more request in a single statement, the row lock of the upsert keeps
+// concurrent instances from going over the limit together. No row is returned when the limit is reached.
+const reserveUsageQuery = `
+INSERT INTO provider_daily_usage (provider, day, sent) VALUES (@provider, @day, 1)
+ON CONFLICT (provider, day) DO UPDATE SET sent = provider_daily_usage.sent + 1
+WHERE provider_daily_usage.sent < @limit
+RETURNING sent`
+
+func (r *PostgresProviderUsageRepository) Reserve(ctx context.Context, provider string, day time.Time, limit int) (bool, error) {
+	var sent []int
+
+	err := r.db.WithContext(ctx).
+		Raw(reserveUsageQuery, map[string]interface{}{
+			"provider": provider,
+			"day":      day.UTC().Format(time.DateOnly),
+			"limit":    limit,
+		}).
+		Scan(&sent).Error
+
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve provider usage: %w", err)
+	}
+
+	return len(sent) == 1, nil
+}
+
+func (r *PostgresProviderUsageRepository) Release(ctx context.Context, provider string, day time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.ProviderDailyUsage{}).
+		Where("provider = ? AND day = ? AND sent > 0", provider, day.UTC().Format(time.DateOnly)).
+		Update("sent", gorm.Expr("sent - 1")).Error
+
+	if err != nil {
+		return fmt.Errorf("failed to release provider usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresProviderUsageRepository) Count(ctx context.Context, provider string, day time.Time) (int, error) {
+	var usage models.ProviderDailyUsage
+
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND day = ?", provider, day.UTC().Format(time.DateOnly)).
+		Take(&usage).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count provider usage: %w", err)
+	}
+
+	return usage.Sent, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/infra/repository/models"
+	"message-scheduler/log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SchedulerSettingsRepository interface {
+	Get(ctx context.Context) (*entity.SchedulerSettingsEntity, error)
+	Save(ctx context.Context, settings *entity.SchedulerSettingsEntity) error
+}
+
+// ErrSchedulerSettingsNotFound is returned by Get until settings were changed through the API.
+var ErrSchedulerSettingsNotFound = errors.New("scheduler settings not stored")
+
+type PostgresSchedulerSettingsRepository struct {
+	db *gorm.DB
+}
+
+func NewSchedulerSettingsRepository(db *gorm.DB) *PostgresSchedulerSettingsRepository {
+	db.Logger = &GormLogger{log.Logger}
+
+	return &PostgresSchedulerSettingsRepository{db: db}
+}
+
+func (r *PostgresSchedulerSettingsRepository) Get(ctx context.Context) (*entity.SchedulerSettingsEntity, error) {
+	var settings models.SchedulerSettings
+
+	err := r.db.WithContext(ctx).Take(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSchedulerSettingsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduler settings: %w", err)
+	}
+
+	return models.MapModelSchedulerSettingsToEntity(&settings), nil
+}
+
+// Save replaces the stored settings.
+func (r *PostgresSchedulerSettingsRepository) Save(ctx context.Context, i *entity.SchedulerSettingsEntity) error {
+	settings := models.MapEntitySchedulerSettingsToModel(i)
+
+	err := r.db.WithContext(ctx).
+		Omit("updated_at").
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"batch_size":               settings.BatchSize,
+				"interval_ms":              settings.IntervalMs,
+				"job_timeout_ms":           settings.JobTimeoutMs,
+				"concurrency":              settings.Concurrency,
+				"preserve_recipient_order": settings.PreserveRecipientOrder,
+				"priority_aging_ms":        settings.PriorityAgingMs,
+				"updated_at":               gorm.Expr("now()"),
+			}),
+		}).
+		Create(settings).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to store scheduler settings")
+		return fmt.Errorf("failed to store scheduler settings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/attempt"
+	"message-scheduler/internal/infra/repository/models"
+	"message-scheduler/log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SendAttemptsRepository interface {
+	Begin(ctx context.Context, sendAttempt *entity.SendAttemptEntity) (*entity.SendAttemptEntity, error)
+	Finish(ctx context.Context, sendAttempt *entity.SendAttemptEntity) error
+}
+
+type PostgresSendAttemptsRepository struct {
+	db *gorm.DB
+}
+
+func NewSendAttemptsRepository(db *gorm.DB) *PostgresSendAttemptsRepository {
+	db.Logger = &GormLogger{log.Logger}
+
+	return &PostgresSendAttemptsRepository{db: db}
+}
+
+// Begin records a pending attempt under its idempotency key. When an attempt with that key exists
+// already, because an earlier send of the same attempt crashed before the message was saved, that
+// attempt is returned unchanged instead.
+func (r *PostgresSendAttemptsRepository) Begin(ctx context.Context, i *entity.SendAttemptEntity) (*entity.SendAttemptEntity, error) {
+	sendAttempt := models.MapEntitySendAttemptToModel(i)
+	sendAttempt.Status = attempt.PENDING
+
+	var recorded models.SendAttempts
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Omit("id", "started_at", "finished_at").
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).
+			Create(sendAttempt).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("idempotency_key = ?", sendAttempt.IdempotencyKey).Take(&recorded).Error
+	})
+
+	if err != nil {
+		log.Logger.Error().Err(err).Str("messageId", i.MessageId).Str("idempotencyKey", i.IdempotencyKey).Msg("Failed to record send attempt")
+		return nil, fmt.Errorf("failed to record send attempt %s: %w", i.IdempotencyKey, err)
+	}
+
+	return models.MapModelSendAttemptToEntity(&recorded), nil
+}
+
+// Finish stores the outcome of the webhook call.
+func (r *PostgresSendAttemptsRepository) Finish(ctx context.Context, i *entity.SendAttemptEntity) error {
+	sendAttempt := models.MapEntitySendAttemptToModel(i)
+
+	err := r.db.WithContext(ctx).
+		Model(&models.SendAttempts{}).
+		Where("idempotency_key = ?", i.IdempotencyKey).
+		Updates(map[string]interface{}{
+			"status":            sendAttempt.Status,
+			"remote_message_id": sendAttempt.RemoteMessageID,
+			"provider":          sendAttempt.Provider,
+			"error":             sendAttempt.Error,
+			"finished_at":       gorm.Expr("now()"),
+		}).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Str("messageId", i.MessageId).Str("idempotencyKey", i.IdempotencyKey).Msg("Failed to record send attempt outcome")
+		return fmt.Errorf("failed to record outcome of send attempt %s: %w", i.IdempotencyKey, err)
+	}
+
+	return nil
+}
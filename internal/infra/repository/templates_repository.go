@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/repository/models"
+	"message-scheduler/log"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TemplatesRepository interface {
+	Create(ctx context.Context, template *entity.TemplateEntity) error
+	AddVersion(ctx context.Context, template *entity.TemplateEntity) error
+	GetTemplate(ctx context.Context, id string, version int) (*entity.TemplateEntity, error)
+	ListTemplates(ctx context.Context) ([]*entity.TemplateEntity, error)
+	ListVersions(ctx context.Context, id string) ([]*entity.TemplateEntity, error)
+	ListPendingRenderings(ctx context.Context, id string) ([]*entity.MessagesEntity, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var (
+	// ErrTemplateNotFound is returned when no template, or no such version of it, exists.
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrTemplateExists is returned by Create when the name is taken.
+	ErrTemplateExists = errors.New("template name already exists")
+	// ErrTemplateInUse is returned by Delete while messages waiting to be sent still use the template.
+	ErrTemplateInUse = errors.New("template is used by pending messages")
+)
+
+// templateVersionColumns selects a TemplateVersionRow from template_versions v joined with templates t.
+const templateVersionColumns = "t.id AS template_id, t.name, v.version, v.content, v.locale, v.variants, v.variables, t.created_at, v.created_at AS version_created_at"
+
+type PostgresTemplatesRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplatesRepository(db *gorm.DB) *PostgresTemplatesRepository {
+	db.Logger = &GormLogger{log.Logger}
+
+	return &PostgresTemplatesRepository{db: db}
+}
+
+// Create stores the template with its content as version 1.
+func (r *PostgresTemplatesRepository) Create(ctx context.Context, i *entity.TemplateEntity) error {
+	template := &models.Templates{Name: i.Name, Version: 1}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Omit("id", "created_at", "updated_at").
+			Clauses(
+				clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true},
+				clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}, {Name: "updated_at"}}},
+			).
+			Create(template)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTemplateExists
+		}
+
+		return insertTemplateVersion(tx, template, i)
+	})
+
+	if errors.Is(err, ErrTemplateExists) {
+		return err
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Str("name", i.Name).Msg("Failed to create template")
+		return fmt.Errorf("failed to create template %s: %w", i.Name, err)
+	}
+
+	log.Logger.Info().Str("templateId", template.ID).Str("name", template.Name).Msg("created template")
+	return nil
+}
+
+// AddVersion stores the content of the template as its next version.
+func (r *PostgresTemplatesRepository) AddVersion(ctx context.Context, i *entity.TemplateEntity) error {
+	if !isSerialID(i.Id) {
+		return ErrTemplateNotFound
+	}
+
+	var template models.Templates
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&template).
+			Clauses(clause.Returning{}).
+			Where("id = ?", i.Id).
+			Updates(map[string]interface{}{
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": gorm.Expr("now()"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTemplateNotFound
+		}
+
+		return insertTemplateVersion(tx, &template, i)
+	})
+
+	if errors.Is(err, ErrTemplateNotFound) {
+		return err
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Str("templateId", i.Id).Msg("Failed to add template version")
+		return fmt.Errorf("failed to add version to template with id=%s: %w", i.Id, err)
+	}
+
+	log.Logger.Info().Str("templateId", template.ID).Int("version", template.Version).Msg("added template version")
+	return nil
+}
+
+// insertTemplateVersion stores the content of i as the current version of template and refreshes i.
+func insertTemplateVersion(tx *gorm.DB, template *models.Templates, i *entity.TemplateEntity) error {
+	version := models.MapEntityTemplateToVersionModel(i, template.ID, template.Version)
+
+	err := tx.
+		Omit("id", "created_at").
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "created_at"}}}).
+		Create(version).Error
+	if err != nil {
+		return err
+	}
+
+	i.Id = template.ID
+	i.Name = template.Name
+	i.Version = version.Version
+	i.CreatedAt = template.CreatedAt
+	i.UpdatedAt = version.CreatedAt
+	return nil
+}
+
+// GetTemplate returns the given version of the template, or its current version when version is 0.
+func (r *PostgresTemplatesRepository) GetTemplate(ctx context.Context, id string, version int) (*entity.TemplateEntity, error) {
+	if !isSerialID(id) {
+		return nil, ErrTemplateNotFound
+	}
+
+	query := r.templateVersions(ctx).Where("t.id = ?", id)
+	if version == 0 {
+		query = query.Where("v.version = t.version")
+	} else {
+		query = query.Where("v.version = ?", version)
+	}
+
+	var rows []*models.TemplateVersionRow
+	if err := query.Limit(1).Scan(&rows).Error; err != nil {
+		log.Logger.Error().Err(err).Str("templateId", id).Int("version", version).Msg("Failed to fetch template from database")
+		return nil, fmt.Errorf("failed to fetch template with id=%s: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+
+	return models.MapTemplateVersionRowToEntity(rows[0]), nil
+}
+
+// ListTemplates returns the current version of every template, ordered by name.
+func (r *PostgresTemplatesRepository) ListTemplates(ctx context.Context) ([]*entity.TemplateEntity, error) {
+	var rows []*models.TemplateVersionRow
+
+	err := r.templateVersions(ctx).
+		Where("v.version = t.version").
+		Order("t.name").
+		Scan(&rows).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Msg("Failed to fetch templates from database")
+		return nil, fmt.Errorf("failed to fetch templates: %w", err)
+	}
+
+	return models.MapTemplateVersionRowsToEntitySlice(rows), nil
+}
+
+// ListVersions returns every version of the template, oldest first.
+func (r *PostgresTemplatesRepository) ListVersions(ctx context.Context, id string) ([]*entity.TemplateEntity, error) {
+	if !isSerialID(id) {
+		return nil, ErrTemplateNotFound
+	}
+
+	var rows []*models.TemplateVersionRow
+	err := r.templateVersions(ctx).
+		Where("t.id = ?", id).
+		Order("v.version").
+		Scan(&rows).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Str("templateId", id).Msg("Failed to fetch template versions from database")
+		return nil, fmt.Errorf("failed to fetch versions of template with id=%s: %w", id, err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+
+	return models.MapTemplateVersionRowsToEntitySlice(rows), nil
+}
+
+// ListPendingRenderings returns every distinct combination of locale and variables among the messages
+// waiting to be sent from the template, the inputs its next version has to render. Only Locale and
+// Variables of the returned messages are set.
+func (r *PostgresTemplatesRepository) ListPendingRenderings(ctx context.Context, id string) ([]*entity.MessagesEntity, error) {
+	if !isSerialID(id) {
+		return nil, nil
+	}
+
+	var rows []*models.Messages
+	err := r.db.WithContext(ctx).
+		Model(&models.Messages{}).
+		Distinct("locale", "variables").
+		Where("template_id = ? AND status IN ?", id,
+			[]string{string(status.UNSENT), string(status.RETRYING), string(status.SENDING)}).
+		Find(&rows).Error
+
+	if err != nil {
+		log.Logger.Error().Err(err).Str("templateId", id).Msg("Failed to fetch pending renderings of template from database")
+		return nil, fmt.Errorf("failed to fetch pending renderings of template with id=%s: %w", id, err)
+	}
+
+	return models.MapModelMessagesToEntitySlice(rows), nil
+}
+
+func (r *PostgresTemplatesRepository) templateVersions(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("template_versions AS v").
+		Select(templateVersionColumns).
+		Joins("JOIN templates AS t ON t.id = v.template_id")
+}
+
+// Delete removes the template and its versions unless messages waiting to be sent still use it. The
+// template row stays locked until the end, so no message can be enqueued from it in the meantime.
+// Messages sent from it keep their content and lose the reference.
+func (r *PostgresTemplatesRepository) Delete(ctx context.Context, id string) error {
+	if !isSerialID(id) {
+		return ErrTemplateNotFound
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var template models.Templates
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(&template).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTemplateNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var inUse bool
+		err = tx.Raw("SELECT EXISTS (SELECT 1 FROM messages WHERE template_id = ? AND status IN ?)",
+			id, []string{string(status.UNSENT), string(status.RETRYING), string(status.SENDING)}).
+			Scan(&inUse).Error
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrTemplateInUse
+		}
+
+		return tx.Where("id = ?", id).Delete(&models.Templates{}).Error
+	})
+
+	if errors.Is(err, ErrTemplateNotFound) || errors.Is(err, ErrTemplateInUse) {
+		return err
+	}
+	if err != nil {
+		log.Logger.Error().Err(err).Str("templateId", id).Msg("Failed to delete template")
+		return fmt.Errorf("failed to delete template with id=%s: %w", id, err)
+	}
+
+	log.Logger.Info().Str("templateId", id).Msg("deleted template")
+	return nil
+}
+
+// isSerialID reports whether id can be a serial primary key, anything else can not match and would
+// only make postgres reject the query.
+func isSerialID(id string) bool {
+	_, err := strconv.ParseInt(id, 10, 64)
+	return err == nil
+}
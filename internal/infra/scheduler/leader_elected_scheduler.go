@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"message-scheduler/internal/port"
+	"message-scheduler/log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LeaderElectedScheduler runs the wrapped scheduler only while this instance holds the leader lock.
+// Followers retry the lock every retryInterval, so another instance takes over once the leader stops
+// or dies and postgres releases its lock.
+type LeaderElectedScheduler struct {
+	inner         port.Scheduler
+	lock          port.LeaderLock
+	retryInterval time.Duration
+	leader        atomic.Bool
+	mutex         sync.Mutex
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+func NewLeaderElectedScheduler(inner port.Scheduler, lock port.LeaderLock, retryInterval time.Duration) *LeaderElectedScheduler {
+	return &LeaderElectedScheduler{
+		inner:         inner,
+		lock:          lock,
+		retryInterval: retryInterval,
+	}
+}
+
+func (s *LeaderElectedScheduler) ScheduleJob(job port.Job, interval time.Duration) {
+	s.inner.ScheduleJob(job, interval)
+}
+
+func (s *LeaderElectedScheduler) Start(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	electionCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	log.Logger.Info().Dur("retry_interval", s.retryInterval).Msg("Starting leader election...")
+
+	go s.elect(electionCtx, s.done)
+}
+
+func (s *LeaderElectedScheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+func (s *LeaderElectedScheduler) elect(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.leader.Load() {
+			if err := s.lock.Check(ctx); err != nil {
+				log.Logger.Error().Err(err).Msg("Lost scheduler leadership")
+				s.stepDown()
+			}
+		} else {
+			acquired, err := s.lock.TryAcquire(ctx)
+			if err != nil {
+				log.Logger.Error().Err(err).Msg("Leader election attempt failed")
+			} else if acquired {
+				log.Logger.Info().Msg("Acquired scheduler leadership, starting jobs")
+				s.leader.Store(true)
+				s.inner.Start(ctx)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if s.leader.Load() {
+				s.stepDown()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *LeaderElectedScheduler) stepDown() {
+	s.leader.Store(false)
+
+	if err := s.inner.Stop(); err != nil {
+		log.Logger.Error().Err(err).Msg("Error stopping scheduler after losing leadership")
+	}
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.lock.Release(releaseCtx); err != nil {
+		log.Logger.Error().Err(err).Msg("Error releasing leader lock")
+	}
+}
+
+func (s *LeaderElectedScheduler) Stop() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.cancel == nil {
+		return nil
+	}
+
+	log.Logger.Info().Msg("Stopping leader election...")
+
+	s.cancel()
+	<-s.done
+
+	s.cancel = nil
+	s.done = nil
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"message-scheduler/mocks"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeLeaderLock struct {
+	mutex     sync.Mutex
+	available bool
+	held      bool
+	checkErr  error
+	released  int
+}
+
+func (l *fakeLeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if !l.available {
+		return false, nil
+	}
+	l.held = true
+	return true, nil
+}
+
+func (l *fakeLeaderLock) Check(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.checkErr != nil {
+		l.held = false
+	}
+	return l.checkErr
+}
+
+func (l *fakeLeaderLock) Release(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.held = false
+	l.released++
+	return nil
+}
+
+func (l *fakeLeaderLock) set(available bool, checkErr error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.available = available
+	l.checkErr = checkErr
+}
+
+func TestLeaderElectedScheduler_StartsJobsOnlyAsLeader(t *testing.T) {
+	inner := &mocks.SchedulerMock{}
+	lock := &fakeLeaderLock{}
+
+	s := NewLeaderElectedScheduler(inner, lock, 10*time.Millisecond)
+	s.Start(context.Background())
+
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, s.IsLeader())
+	inner.AssertNotCalled(t, "Start", mock.Anything)
+
+	inner.On("Start", mock.Anything).Return().Once()
+	inner.On("Stop").Return(nil).Once()
+	lock.set(true, nil)
+
+	assert.Eventually(t, s.IsLeader, time.Second, 5*time.Millisecond)
+
+	assert.NoError(t, s.Stop())
+	assert.False(t, s.IsLeader())
+	assert.Equal(t, 1, lock.released)
+	inner.AssertExpectations(t)
+}
+
+func TestLeaderElectedScheduler_StepsDownWhenLockIsLost(t *testing.T) {
+	inner := &mocks.SchedulerMock{}
+	lock := &fakeLeaderLock{available: true}
+
+	inner.On("Start", mock.Anything).Return()
+	inner.On("Stop").Return(nil)
+
+	s := NewLeaderElectedScheduler(inner, lock, 10*time.Millisecond)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, s.IsLeader, time.Second, 5*time.Millisecond)
+
+	lock.set(false, fmt.Errorf("connection reset"))
+
+	assert.Eventually(t, func() bool { return !s.IsLeader() }, time.Second, 5*time.Millisecond)
+	inner.AssertCalled(t, "Stop")
+}
@@ -8,6 +8,8 @@ import (
 	"time"
 )
 
+const defaultJobTimeout = 30 * time.Second
+
 type ScheduledJob struct {
 	job      port.Job
 	interval time.Duration
@@ -56,6 +58,12 @@ func (s *SimpleScheduler) Start(ctx context.Context) {
 	log.Logger.Info().Int("job_count", len(s.jobs)).Msg("Starting scheduler...")
 
 	for _, scheduledJob := range s.jobs {
+		// drop a stop signal left over from a previous Stop, otherwise a restarted job would exit immediately
+		select {
+		case <-scheduledJob.stop:
+		default:
+		}
+
 		s.wg.Add(1)
 		go s.runJob(scheduledJob)
 	}
@@ -70,6 +78,7 @@ func (s *SimpleScheduler) runJob(scheduledJob *ScheduledJob) {
 	defer scheduledJob.ticker.Stop()
 
 	s.executeJob(scheduledJob.job)
+	s.refreshInterval(scheduledJob)
 
 	for {
 		select {
@@ -87,10 +96,31 @@ func (s *SimpleScheduler) runJob(scheduledJob *ScheduledJob) {
 
 		case <-scheduledJob.ticker.C:
 			s.executeJob(scheduledJob.job)
+			s.refreshInterval(scheduledJob)
 		}
 	}
 }
 
+func (s *SimpleScheduler) refreshInterval(scheduledJob *ScheduledJob) {
+	dynamicJob, ok := scheduledJob.job.(port.DynamicJob)
+	if !ok {
+		return
+	}
+
+	interval := dynamicJob.Interval()
+	if interval <= 0 || interval == scheduledJob.interval {
+		return
+	}
+
+	scheduledJob.interval = interval
+	scheduledJob.ticker.Reset(interval)
+
+	log.Logger.Info().
+		Str("job_name", scheduledJob.job.Name()).
+		Dur("interval", interval).
+		Msg("Job interval changed")
+}
+
 func (s *SimpleScheduler) executeJob(job port.Job) {
 	start := time.Now()
 
@@ -98,7 +128,12 @@ func (s *SimpleScheduler) executeJob(job port.Job) {
 		Str("job_name", job.Name()).
 		Msg("Executing scheduled job")
 
-	jobCtx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	timeout := defaultJobTimeout
+	if dynamicJob, ok := job.(port.DynamicJob); ok && dynamicJob.Timeout() > 0 {
+		timeout = dynamicJob.Timeout()
+	}
+
+	jobCtx, cancel := context.WithTimeout(s.ctx, timeout)
 	defer cancel()
 
 	err := job.Execute(jobCtx)
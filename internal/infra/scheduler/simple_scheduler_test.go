@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dynamicTestJob struct {
+	mutex     sync.Mutex
+	interval  time.Duration
+	timeout   time.Duration
+	runs      atomic.Int32
+	deadlines []time.Duration
+}
+
+func (j *dynamicTestJob) Execute(ctx context.Context) error {
+	deadline, _ := ctx.Deadline()
+
+	j.mutex.Lock()
+	j.deadlines = append(j.deadlines, time.Until(deadline))
+	j.mutex.Unlock()
+
+	j.runs.Add(1)
+	return nil
+}
+
+func (j *dynamicTestJob) Name() string { return "DynamicTestJob" }
+
+func (j *dynamicTestJob) Interval() time.Duration {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.interval
+}
+
+func (j *dynamicTestJob) Timeout() time.Duration {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.timeout
+}
+
+func TestSimpleScheduler_AppliesDynamicIntervalAndTimeout(t *testing.T) {
+	job := &dynamicTestJob{interval: time.Hour, timeout: time.Minute}
+
+	s := NewSimpleScheduler()
+	s.ScheduleJob(job, 20*time.Millisecond)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	// the first run switches the ticker from 20ms to the job's own interval
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), job.runs.Load())
+
+	job.mutex.Lock()
+	assert.InDelta(t, float64(time.Minute), float64(job.deadlines[0]), float64(time.Second))
+	job.mutex.Unlock()
+}
+
+func TestSimpleScheduler_RestartAfterStop(t *testing.T) {
+	job := &dynamicTestJob{interval: 10 * time.Millisecond, timeout: time.Second}
+
+	s := NewSimpleScheduler()
+	s.ScheduleJob(job, 10*time.Millisecond)
+
+	s.Start(context.Background())
+	assert.NoError(t, s.Stop())
+
+	runs := job.runs.Load()
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Eventually(t, func() bool { return job.runs.Load() >= runs+3 }, time.Second, 5*time.Millisecond)
+}
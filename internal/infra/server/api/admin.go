@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"message-scheduler/internal/application"
+	"message-scheduler/internal/infra/server/api/request"
+	. "message-scheduler/internal/infra/server/api/response"
+	"message-scheduler/internal/port"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSchedulerSettingsHandler godoc
+// @Summary  Get Scheduler Settings
+// @Description  Return the batch size, interval, job timeout, dispatch concurrency and priority aging currently used by the message processing job
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} SchedulerSettingsResponse "Current scheduler settings"
+// @Failure      500 {object} map[string]string "Stored settings could not be loaded"
+// @Router       /admin/scheduler-settings [get]
+func GetSchedulerSettingsHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		settings, err := service.RefreshSchedulerSettings(ctx.Context())
+		if err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load scheduler settings"})
+		}
+
+		return ctx.JSON(toSchedulerSettingsResponse(settings))
+	}
+}
+
+// UpdateSchedulerSettingsHandler godoc
+// @Summary  Update Scheduler Settings
+// @Description  Change the batch size, interval, job timeout, dispatch concurrency or priority aging without a restart. The change is stored for all instances and applied by the one running the scheduler on its next tick.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        settings body request.UpdateSchedulerSettingsRequest true "Settings to change"
+// @Success      200 {object} SchedulerSettingsResponse "Updated scheduler settings"
+// @Failure      400 {object} map[string]string "Invalid settings"
+// @Router       /admin/scheduler-settings [patch]
+func UpdateSchedulerSettingsHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req request.UpdateSchedulerSettingsRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		// start from the stored settings, this instance may not have applied the latest change yet
+		settings, err := service.RefreshSchedulerSettings(ctx.Context())
+		if err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load scheduler settings"})
+		}
+		if req.BatchSize != nil {
+			settings.BatchSize = *req.BatchSize
+		}
+		if req.Interval != nil {
+			settings.Interval = time.Duration(*req.Interval) * time.Millisecond
+		}
+		if req.JobTimeout != nil {
+			settings.JobTimeout = time.Duration(*req.JobTimeout) * time.Millisecond
+		}
+		if req.Concurrency != nil {
+			settings.Concurrency = *req.Concurrency
+		}
+		if req.PreserveRecipientOrder != nil {
+			settings.PreserveRecipientOrder = *req.PreserveRecipientOrder
+		}
+		if req.PriorityAging != nil {
+			settings.PriorityAging = time.Duration(*req.PriorityAging) * time.Millisecond
+		}
+
+		if err := service.UpdateSchedulerSettings(ctx.Context(), settings); err != nil {
+			var validationErr port.ValidationError
+			if errors.As(err, &validationErr) {
+				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErr.Error()})
+			}
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update scheduler settings"})
+		}
+
+		return ctx.JSON(toSchedulerSettingsResponse(service.SchedulerSettings()))
+	}
+}
+
+func toSchedulerSettingsResponse(settings application.SchedulerSettings) SchedulerSettingsResponse {
+	return SchedulerSettingsResponse{
+		BatchSize:              settings.BatchSize,
+		Interval:               settings.Interval.Milliseconds(),
+		JobTimeout:             settings.JobTimeout.Milliseconds(),
+		Concurrency:            settings.Concurrency,
+		PreserveRecipientOrder: settings.PreserveRecipientOrder,
+		PriorityAging:          settings.PriorityAging.Milliseconds(),
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"message-scheduler/internal/application"
+	"message-scheduler/internal/infra/client/webhook"
+	"message-scheduler/internal/infra/server/api/request"
+	. "message-scheduler/internal/infra/server/api/response"
+	"message-scheduler/signature"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeliveryReceiptHandler godoc
+// @Summary  Delivery Receipt
+// @Description  Called by the provider to report whether a sent message was delivered. The body must be signed with one of the provider's callback secrets in the X-Webhook-Signature header. Receipts are matched by the message id the provider's webhook returned; repeated or out-of-order receipts are accepted without changing the message. Without a provider in the path the receipt is for the default provider.
+// @Tags         callbacks
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Provider that sent the message"
+// @Param        X-Webhook-Signature header string true "Signature of the body, t=<unix time>,v1=<hex HMAC-SHA256>"
+// @Param        receipt body request.DeliveryReceiptRequest true "Delivery receipt"
+// @Success      200 {object} DeliveryReceiptResponse "Message status after the receipt"
+// @Failure      400 {object} map[string]string "Invalid receipt"
+// @Failure      401 {object} map[string]string "Missing or invalid signature"
+// @Failure      404 {object} map[string]string "No message with this id, retry later"
+// @Failure      409 {object} map[string]string "Message can not take a receipt yet, retry later"
+// @Router       /callbacks/delivery/{provider} [post]
+func DeliveryReceiptHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		provider := ctx.Params("provider", webhook.DefaultProvider)
+
+		// the signature covers the raw body, so it is checked before the body is parsed
+		if err := service.VerifyDeliveryCallback(provider, ctx.Get(signature.Header), ctx.Body()); err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		var req request.DeliveryReceiptRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		message, err := service.RecordDeliveryReceipt(ctx.Context(), application.DeliveryReceipt{
+			Provider:        provider,
+			RemoteMessageID: req.MessageID,
+			Status:          application.ReceiptStatus(req.Status),
+			Timestamp:       req.Timestamp,
+			Reason:          req.Reason,
+		})
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to record delivery receipt")
+		}
+
+		return ctx.JSON(DeliveryReceiptResponse{ID: message.Id, Status: string(message.Status)})
+	}
+}
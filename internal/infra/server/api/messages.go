@@ -0,0 +1,425 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"message-scheduler/internal/application"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/domain/types/status"
+	"message-scheduler/internal/infra/server/api/request"
+	. "message-scheduler/internal/infra/server/api/response"
+	"message-scheduler/internal/port"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxBatchSize       = 50000
+	ndjsonContentType  = "application/x-ndjson"
+	batchItemAccepted  = "accepted"
+	batchItemRejected  = "rejected"
+	maxNDJSONLineBytes = 64 * 1024
+)
+
+// CreateMessageHandler godoc
+// @Summary  Create Message
+// @Description  Enqueue a new message to be sent by the scheduler, right away or once its sendAt time has come. Give either content or a templateId with the variables its placeholders need.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        message body request.CreateMessageRequest true "Message to enqueue"
+// @Success      201 {object} CreateMessageResponse "Message enqueued successfully"
+// @Failure      400 {object} map[string]string "Invalid message"
+// @Router       /messages [post]
+func CreateMessageHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req request.CreateMessageRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		message := &entity.MessagesEntity{
+			Phone:      req.Phone,
+			Content:    req.Content,
+			SendAt:     req.SendAt,
+			Priority:   priority.MessagePriority(req.Priority),
+			Tags:       req.Tags,
+			TemplateId: req.TemplateID,
+			Variables:  req.Variables,
+			Locale:     req.Locale,
+		}
+
+		if err := service.CreateMessage(ctx.Context(), message); err != nil {
+			var validationErr port.ValidationError
+			if errors.As(err, &validationErr) {
+				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErr.Error()})
+			}
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create message"})
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(CreateMessageResponse{
+			ID:       message.Id,
+			Status:   string(message.Status),
+			SendAt:   message.SendAt,
+			Priority: string(message.Priority),
+			Version:  message.Version,
+		})
+	}
+}
+
+// CreateMessagesBatchHandler godoc
+// @Summary  Create Messages In Batch
+// @Description  Enqueue many messages at once. Accepts a JSON array or an NDJSON body (Content-Type: application/x-ndjson) and reports acceptance or the validation error for every item.
+// @Tags         messages
+// @Accept       json
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param        messages body []request.CreateMessageRequest true "Messages to enqueue"
+// @Success      200 {object} CreateMessagesBatchResponse "Per-item results"
+// @Failure      400 {object} map[string]string "Malformed batch"
+// @Router       /messages/batch [post]
+func CreateMessagesBatchHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var (
+			items []json.RawMessage
+			err   error
+		)
+
+		if IsNDJSONBatch(ctx) {
+			items, err = readNDJSON(ctx.Context().RequestBodyStream(), ctx.Body)
+			if err != nil {
+				// lines left unread are still on the connection, it cannot carry another request
+				ctx.Context().SetConnectionClose()
+			}
+		} else {
+			err = json.Unmarshal(ctx.Body(), &items)
+		}
+		if errors.Is(err, errBatchTooLarge) || len(items) > maxBatchSize {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Batch must contain at most %d messages", maxBatchSize)})
+		}
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+		}
+
+		if len(items) == 0 {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Batch must contain at least one message"})
+		}
+
+		results := make([]BatchMessageResult, len(items))
+		messages := make([]*entity.MessagesEntity, 0, len(items))
+		positions := make([]int, 0, len(items))
+
+		for i, item := range items {
+			var req request.CreateMessageRequest
+			if err := json.Unmarshal(item, &req); err != nil {
+				results[i] = BatchMessageResult{Index: i, Status: batchItemRejected, Error: "invalid JSON: " + err.Error()}
+				continue
+			}
+
+			messages = append(messages, &entity.MessagesEntity{
+				Phone:      req.Phone,
+				Content:    req.Content,
+				SendAt:     req.SendAt,
+				Priority:   priority.MessagePriority(req.Priority),
+				Tags:       req.Tags,
+				TemplateId: req.TemplateID,
+				Variables:  req.Variables,
+				Locale:     req.Locale,
+			})
+			positions = append(positions, i)
+		}
+
+		created, err := service.CreateMessages(ctx.Context(), messages)
+		if err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create messages"})
+		}
+
+		for _, result := range created {
+			index := positions[result.Index]
+			if result.Err != nil {
+				results[index] = BatchMessageResult{Index: index, Status: batchItemRejected, Error: result.Err.Error()}
+				continue
+			}
+			results[index] = BatchMessageResult{Index: index, ID: result.Message.Id, Status: batchItemAccepted}
+		}
+
+		response := CreateMessagesBatchResponse{Results: results}
+		for _, result := range results {
+			if result.Status == batchItemAccepted {
+				response.Accepted++
+			} else {
+				response.Rejected++
+			}
+		}
+
+		return ctx.JSON(response)
+	}
+}
+
+// CancelMessageHandler godoc
+// @Summary  Cancel Message
+// @Description  Cancel a message that has not been picked up for sending yet
+// @Tags         messages
+// @Produce      json
+// @Param        id path string true "Message id"
+// @Param        version query int false "Expected message version"
+// @Success      200 {object} MessageResponse "Cancelled message"
+// @Failure      404 {object} map[string]string "Message not found"
+// @Failure      409 {object} map[string]string "Message is already being sent, sent or changed"
+// @Router       /messages/{id} [delete]
+func CancelMessageHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		version := ctx.QueryInt("version", 0)
+
+		message, err := service.CancelMessage(ctx.Context(), ctx.Params("id"), version)
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to cancel message")
+		}
+
+		return ctx.JSON(toMessageResponse(message))
+	}
+}
+
+// UpdateMessageHandler godoc
+// @Summary  Update Message
+// @Description  Change the send time, content or priority of a message that has not been picked up for sending yet
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Message id"
+// @Param        changes body request.UpdateMessageRequest true "Fields to change"
+// @Success      200 {object} MessageResponse "Updated message"
+// @Failure      400 {object} map[string]string "Invalid changes"
+// @Failure      404 {object} map[string]string "Message not found"
+// @Failure      409 {object} map[string]string "Message is already being sent, sent or changed"
+// @Router       /messages/{id} [patch]
+func UpdateMessageHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req request.UpdateMessageRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		changes := application.MessageChanges{
+			Content: req.Content,
+			SendAt:  req.SendAt,
+			Version: req.Version,
+		}
+		if req.Priority != nil {
+			messagePriority := priority.MessagePriority(*req.Priority)
+			changes.Priority = &messagePriority
+		}
+
+		message, err := service.UpdateMessage(ctx.Context(), ctx.Params("id"), changes)
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to update message")
+		}
+
+		return ctx.JSON(toMessageResponse(message))
+	}
+}
+
+// ListMessagesHandler godoc
+// @Summary  Search Messages
+// @Description  List messages newest first, narrowed by any combination of filters. Follow nextCursor to fetch the next page.
+// @Tags         messages
+// @Produce      json
+// @Param        status query string false "Comma-separated statuses, e.g. failed,retrying"
+// @Param        phone query string false "Recipient phone number"
+// @Param        remoteMessageId query string false "Message id returned by the webhook"
+// @Param        tag query []string false "Tags the message must all carry, repeated or comma-separated" collectionFormat(multi)
+// @Param        from query string false "Created at or after, RFC 3339"
+// @Param        to query string false "Created before, RFC 3339"
+// @Param        cursor query string false "nextCursor of the previous page"
+// @Param        limit query int false "Page size (default: 50, max: 100)"
+// @Success      200 {object} ListMessagesResponse "Messages matching the filters"
+// @Failure      400 {object} map[string]string "Invalid filter"
+// @Router       /messages [get]
+func ListMessagesHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		query := application.MessageQuery{
+			Phone:           ctx.Query("phone"),
+			RemoteMessageID: ctx.Query("remoteMessageId"),
+			CreatedFrom:     ctx.Query("from"),
+			CreatedTo:       ctx.Query("to"),
+			Cursor:          ctx.Query("cursor"),
+		}
+
+		for _, messageStatus := range splitQueryList(ctx.Query("status")) {
+			query.Statuses = append(query.Statuses, status.MessageStatus(strings.ToLower(messageStatus)))
+		}
+		for _, tag := range ctx.Context().QueryArgs().PeekMulti("tag") {
+			query.Tags = append(query.Tags, splitQueryList(string(tag))...)
+		}
+
+		if limitParam := ctx.Query("limit"); limitParam != "" {
+			limit, err := strconv.Atoi(limitParam)
+			if err != nil || limit <= 0 {
+				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid limit parameter. Must be a positive integer"})
+			}
+			query.Limit = limit
+		}
+
+		page, err := service.SearchMessages(ctx.Context(), query)
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to search messages")
+		}
+
+		response := ListMessagesResponse{
+			Messages:   make([]MessageResponse, len(page.Messages)),
+			NextCursor: page.NextCursor,
+		}
+		for i, message := range page.Messages {
+			response.Messages[i] = toMessageResponse(message)
+		}
+
+		return ctx.JSON(response)
+	}
+}
+
+// GetMessageHandler godoc
+// @Summary  Get Message
+// @Description  Retrieve a message together with the history of its status and content changes
+// @Tags         messages
+// @Produce      json
+// @Param        id path string true "Message id"
+// @Success      200 {object} MessageDetailResponse "Message and its history, oldest first"
+// @Failure      404 {object} map[string]string "Message not found"
+// @Router       /messages/{id} [get]
+func GetMessageHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		details, err := service.GetMessageDetails(ctx.Context(), ctx.Params("id"))
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to retrieve message")
+		}
+
+		response := MessageDetailResponse{
+			MessageResponse: toMessageResponse(details.Message),
+			History:         make([]MessageHistoryResponse, len(details.History)),
+		}
+		for i, entry := range details.History {
+			response.History[i] = toMessageHistoryResponse(entry)
+		}
+
+		return ctx.JSON(response)
+	}
+}
+
+func messageChangeError(ctx *fiber.Ctx, err error, fallback string) error {
+	var (
+		validationErr port.ValidationError
+		notFoundErr   port.NotFoundError
+		conflictErr   port.ConflictError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": validationErr.Error()})
+	case errors.As(err, &notFoundErr):
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": notFoundErr.Error()})
+	case errors.As(err, &conflictErr):
+		return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{"error": conflictErr.Error()})
+	default:
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+	}
+}
+
+func toMessageResponse(message *entity.MessagesEntity) MessageResponse {
+	tags := message.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return MessageResponse{
+		ID:                 message.Id,
+		Phone:              message.Phone,
+		Content:            message.Content,
+		Status:             string(message.Status),
+		Priority:           string(message.Priority),
+		Tags:               tags,
+		SendAt:             message.SendAt,
+		SentAt:             message.SentAt,
+		RemoteMessageID:    message.RemoteMessageId,
+		Provider:           message.Provider,
+		Attempts:           message.Attempts,
+		LastError:          message.LastError,
+		NextAttemptAt:      message.NextAttemptAt,
+		DeliveryReportedAt: message.DeliveryReportedAt,
+		DeliveryError:      message.DeliveryError,
+		Version:            message.Version,
+		CreatedAt:          message.CreatedAt,
+		UpdatedAt:          message.UpdatedAt,
+		TemplateID:         message.TemplateId,
+		TemplateVersion:    message.TemplateVersion,
+		Variables:          message.Variables,
+		Locale:             message.Locale,
+	}
+}
+
+func toMessageHistoryResponse(entry *entity.MessageHistoryEntity) MessageHistoryResponse {
+	return MessageHistoryResponse{
+		Status:          string(entry.Status),
+		Priority:        string(entry.Priority),
+		Content:         entry.Content,
+		SendAt:          entry.SendAt,
+		Attempts:        entry.Attempts,
+		LastError:       entry.LastError,
+		RemoteMessageID: entry.RemoteMessageId,
+		Version:         entry.Version,
+		RecordedAt:      entry.RecordedAt,
+	}
+}
+
+// errBatchTooLarge stops reading an NDJSON batch at its first line beyond maxBatchSize.
+var errBatchTooLarge = errors.New("batch too large")
+
+// IsNDJSONBatch reports whether the request is an NDJSON batch, whose body CreateMessagesBatchHandler
+// reads from the connection line by line instead of all at once.
+func IsNDJSONBatch(ctx *fiber.Ctx) bool {
+	return ctx.Method() == fiber.MethodPost && ctx.Path() == "/messages/batch" &&
+		bytes.HasPrefix(ctx.Request().Header.ContentType(), []byte(ndjsonContentType))
+}
+
+// readNDJSON reads one message per line from stream as the lines arrive, so only the lines read so
+// far are held in memory, and gives up once a line exceeds maxNDJSONLineBytes or there are more than
+// maxBatchSize of them. Without a stream, e.g. when the server does not stream request bodies, body
+// is read instead.
+func readNDJSON(stream io.Reader, body func() []byte) ([]json.RawMessage, error) {
+	if stream == nil {
+		stream = bytes.NewReader(body())
+	}
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 4096), maxNDJSONLineBytes)
+
+	var items []json.RawMessage
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(items) == maxBatchSize {
+			return nil, errBatchTooLarge
+		}
+		items = append(items, json.RawMessage(bytes.Clone(line)))
+	}
+
+	return items, scanner.Err()
+}
+
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
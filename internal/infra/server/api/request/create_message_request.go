@@ -0,0 +1,14 @@
+package request
+
+type CreateMessageRequest struct {
+	Phone    string   `json:"phone" example:"+905551234567"`
+	Content  string   `json:"content" example:"Hello, World!"`
+	SendAt   string   `json:"sendAt,omitempty" example:"2025-01-02T09:00:00+03:00"` // RFC 3339, omit to send right away
+	Priority string   `json:"priority,omitempty" example:"normal" enums:"critical,high,normal,bulk"`
+	Tags     []string `json:"tags,omitempty" example:"campaign-42"`
+	// TemplateID renders the content from a stored template instead, filling its placeholders from Variables.
+	TemplateID string            `json:"templateId,omitempty" example:"7"`
+	Variables  map[string]string `json:"variables,omitempty"`
+	// Locale is the recipient's language tag, it picks the matching variant of the template.
+	Locale string `json:"locale,omitempty" example:"tr-TR"`
+}
@@ -0,0 +1,9 @@
+package request
+
+type CreateTemplateRequest struct {
+	Name    string `json:"name" example:"otp"`
+	Content string `json:"content" example:"Hi {{name}}, your code is {{code}}"` // {{variable}} placeholders
+	// Locale is the language of Content, Variants hold the content for other locales.
+	Locale   string            `json:"locale,omitempty" example:"en"`
+	Variants map[string]string `json:"variants,omitempty"`
+}
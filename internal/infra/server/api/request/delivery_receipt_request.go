@@ -0,0 +1,8 @@
+package request
+
+type DeliveryReceiptRequest struct {
+	MessageID string `json:"messageId" example:"whatsapp-msg-123"` // id returned by the webhook when the message was sent
+	Status    string `json:"status" example:"delivered" enums:"delivered,read,failed"`
+	Timestamp string `json:"timestamp,omitempty" example:"2025-01-02T06:00:05Z"` // RFC 3339, defaults to the time of the callback
+	Reason    string `json:"reason,omitempty" example:"recipient unreachable"`
+}
@@ -0,0 +1,8 @@
+package request
+
+type UpdateMessageRequest struct {
+	Content  *string `json:"content,omitempty" example:"Hello again!"`
+	SendAt   *string `json:"sendAt,omitempty" example:"2025-01-02T10:00:00+03:00"` // RFC 3339
+	Priority *string `json:"priority,omitempty" example:"high" enums:"critical,high,normal,bulk"`
+	Version  int     `json:"version,omitempty" example:"1"` // rejects the change when the message has moved on
+}
@@ -0,0 +1,12 @@
+package request
+
+// UpdateSchedulerSettingsRequest changes only the fields that are present.
+type UpdateSchedulerSettingsRequest struct {
+	BatchSize  *int `json:"batchSize,omitempty" example:"10"`
+	Interval   *int `json:"interval,omitempty" example:"60000"`   // in ms
+	JobTimeout *int `json:"jobTimeout,omitempty" example:"30000"` // in ms
+
+	Concurrency            *int  `json:"concurrency,omitempty" example:"8"`
+	PreserveRecipientOrder *bool `json:"preserveRecipientOrder,omitempty" example:"true"`
+	PriorityAging          *int  `json:"priorityAging,omitempty" example:"300000"` // in ms
+}
@@ -0,0 +1,8 @@
+package request
+
+type UpdateTemplateRequest struct {
+	Content string `json:"content" example:"{{code}} is your code, {{name}}"`
+	Locale  string `json:"locale,omitempty" example:"en"`
+	// Variants replace those of the previous version, which are kept when Variants is left out.
+	Variants map[string]string `json:"variants,omitempty"`
+}
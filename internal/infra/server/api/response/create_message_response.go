@@ -0,0 +1,9 @@
+package response
+
+type CreateMessageResponse struct {
+	ID       string `json:"id" example:"42"`
+	Status   string `json:"status" example:"unsent"`
+	SendAt   string `json:"sendAt" example:"2025-01-02T06:00:00Z"`
+	Priority string `json:"priority" example:"normal"`
+	Version  int    `json:"version" example:"1"`
+}
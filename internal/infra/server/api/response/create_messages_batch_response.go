@@ -0,0 +1,14 @@
+package response
+
+type BatchMessageResult struct {
+	Index  int    `json:"index" example:"0"`
+	ID     string `json:"id,omitempty" example:"42"`
+	Status string `json:"status" example:"accepted"`
+	Error  string `json:"error,omitempty" example:"phone must be in E.164 format, e.g. +905551234567"`
+}
+
+type CreateMessagesBatchResponse struct {
+	Accepted int                  `json:"accepted" example:"1"`
+	Rejected int                  `json:"rejected" example:"0"`
+	Results  []BatchMessageResult `json:"results"`
+}
@@ -0,0 +1,6 @@
+package response
+
+type DeliveryReceiptResponse struct {
+	ID     string `json:"id" example:"42"`
+	Status string `json:"status" example:"delivered"`
+}
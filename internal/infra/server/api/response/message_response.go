@@ -0,0 +1,50 @@
+package response
+
+type MessageResponse struct {
+	ID                 string   `json:"id" example:"42"`
+	Phone              string   `json:"phone" example:"+905551234567"`
+	Content            string   `json:"content" example:"Hello, World!"`
+	Status             string   `json:"status" example:"unsent"`
+	Priority           string   `json:"priority" example:"normal"`
+	Tags               []string `json:"tags" example:"campaign-42"`
+	SendAt             string   `json:"sendAt" example:"2025-01-02T06:00:00Z"`
+	SentAt             string   `json:"sentAt,omitempty" example:"2025-01-02T06:00:03Z"`
+	RemoteMessageID    string   `json:"remoteMessageId,omitempty" example:"whatsapp-msg-123"`
+	Provider           string   `json:"provider,omitempty" example:"default"`
+	Attempts           int      `json:"attempts" example:"0"`
+	LastError          string   `json:"lastError,omitempty" example:"webhook returned 503"`
+	NextAttemptAt      string   `json:"nextAttemptAt,omitempty" example:"2025-01-02T06:01:00Z"`
+	DeliveryReportedAt string   `json:"deliveryReportedAt,omitempty" example:"2025-01-02T06:00:05Z"`
+	DeliveryError      string   `json:"deliveryError,omitempty" example:"recipient unreachable"`
+	Version            int      `json:"version" example:"2"`
+	CreatedAt          string   `json:"createdAt" example:"2025-01-01T10:00:00Z"`
+	UpdatedAt          string   `json:"updatedAt" example:"2025-01-01T10:05:00Z"`
+	// TemplateID is set on messages enqueued from a template, TemplateVersion is the version rendered last.
+	TemplateID      string            `json:"templateId,omitempty" example:"7"`
+	TemplateVersion int               `json:"templateVersion,omitempty" example:"2"`
+	Variables       map[string]string `json:"variables,omitempty"`
+	Locale          string            `json:"locale,omitempty" example:"tr-TR"`
+}
+
+type ListMessagesResponse struct {
+	Messages []MessageResponse `json:"messages"`
+	// NextCursor fetches the following page, it is empty on the last one.
+	NextCursor string `json:"nextCursor,omitempty" example:"eyJjIjoiMjAyNS0wMS0wMVQxMDowMDowMFoiLCJpIjoiNDIifQ"`
+}
+
+type MessageHistoryResponse struct {
+	Status          string `json:"status" example:"unsent"`
+	Priority        string `json:"priority" example:"normal"`
+	Content         string `json:"content" example:"Hello, World!"`
+	SendAt          string `json:"sendAt" example:"2025-01-02T06:00:00Z"`
+	Attempts        int    `json:"attempts" example:"0"`
+	LastError       string `json:"lastError,omitempty" example:"webhook returned 503"`
+	RemoteMessageID string `json:"remoteMessageId,omitempty" example:"whatsapp-msg-123"`
+	Version         int    `json:"version" example:"1"`
+	RecordedAt      string `json:"recordedAt" example:"2025-01-01T10:00:00Z"`
+}
+
+type MessageDetailResponse struct {
+	MessageResponse
+	History []MessageHistoryResponse `json:"history"`
+}
@@ -0,0 +1,11 @@
+package response
+
+type SchedulerSettingsResponse struct {
+	BatchSize  int   `json:"batchSize" example:"2"`
+	Interval   int64 `json:"interval" example:"120000"`  // in ms
+	JobTimeout int64 `json:"jobTimeout" example:"30000"` // in ms
+
+	Concurrency            int   `json:"concurrency" example:"4"`
+	PreserveRecipientOrder bool  `json:"preserveRecipientOrder" example:"true"`
+	PriorityAging          int64 `json:"priorityAging" example:"300000"` // in ms
+}
@@ -5,10 +5,13 @@ type SentMessageResponse struct {
 	Phone           string `json:"phone" example:"+905551234567"`
 	Content         string `json:"content" example:"Hello, World!"`
 	Status          string `json:"status" example:"SENT"`
+	Priority        string `json:"priority" example:"normal"`
 	CreatedAt       string `json:"createdAt" example:"2023-10-01T10:00:00Z"`
 	UpdatedAt       string `json:"updatedAt" example:"2023-10-01T10:05:00Z"`
+	SendAt          string `json:"sendAt" example:"2023-10-01T10:00:00Z"`
 	SentAt          string `json:"sentAt" example:"2023-10-01T10:05:00Z"`
 	RemoteMessageID string `json:"remoteMessageId" example:"whatsapp-msg-123"`
+	Provider        string `json:"provider,omitempty" example:"default"`
 }
 
 type GetSentMessagesResponse struct {
@@ -0,0 +1,22 @@
+package response
+
+type TemplateResponse struct {
+	ID        string            `json:"id" example:"7"`
+	Name      string            `json:"name" example:"otp"`
+	Version   int               `json:"version" example:"2"`
+	Content   string            `json:"content" example:"Hi {{name}}, your code is {{code}}"`
+	Locale    string            `json:"locale,omitempty" example:"en"`
+	Variants  map[string]string `json:"variants"`
+	Variables []string          `json:"variables" example:"name,code"`
+	CreatedAt string            `json:"createdAt" example:"2025-01-01T10:00:00Z"`
+	// UpdatedAt is when this version was created.
+	UpdatedAt string `json:"updatedAt" example:"2025-01-01T10:05:00Z"`
+}
+
+type ListTemplatesResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+}
+
+type TemplateVersionsResponse struct {
+	Versions []TemplateResponse `json:"versions"`
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"message-scheduler/internal/application"
+	"message-scheduler/internal/domain/entity"
+	"message-scheduler/internal/infra/server/api/request"
+	. "message-scheduler/internal/infra/server/api/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateTemplateHandler godoc
+// @Summary  Create Template
+// @Description  Store a message template as version 1. {{variable}} placeholders in the content are filled from the variables of every message enqueued with its templateId. Variants translate the content for other locales; a message gets the variant of its locale, then of the language alone, e.g. tr-TR then tr, and the content otherwise.
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        template body request.CreateTemplateRequest true "Template to create"
+// @Success      201 {object} TemplateResponse "Created template"
+// @Failure      400 {object} map[string]string "Invalid template"
+// @Failure      409 {object} map[string]string "Template name already exists"
+// @Router       /templates [post]
+func CreateTemplateHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req request.CreateTemplateRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		template := &entity.TemplateEntity{Name: req.Name, Content: req.Content, Locale: req.Locale, Variants: req.Variants}
+		if err := service.CreateTemplate(ctx.Context(), template); err != nil {
+			return messageChangeError(ctx, err, "Failed to create template")
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(toTemplateResponse(template))
+	}
+}
+
+// ListTemplatesHandler godoc
+// @Summary  List Templates
+// @Description  List the current version of every template, ordered by name
+// @Tags         templates
+// @Produce      json
+// @Success      200 {object} ListTemplatesResponse "Templates"
+// @Router       /templates [get]
+func ListTemplatesHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		templates, err := service.ListTemplates(ctx.Context())
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to list templates")
+		}
+
+		response := ListTemplatesResponse{Templates: make([]TemplateResponse, len(templates))}
+		for i, template := range templates {
+			response.Templates[i] = toTemplateResponse(template)
+		}
+
+		return ctx.JSON(response)
+	}
+}
+
+// GetTemplateHandler godoc
+// @Summary  Get Template
+// @Description  Retrieve the current or an earlier version of a template
+// @Tags         templates
+// @Produce      json
+// @Param        id path string true "Template id"
+// @Param        version query int false "Version to retrieve (default: current)"
+// @Success      200 {object} TemplateResponse "Template"
+// @Failure      404 {object} map[string]string "Template or version not found"
+// @Router       /templates/{id} [get]
+func GetTemplateHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		version := ctx.QueryInt("version", 0)
+		if version < 0 {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid version parameter. Must be a positive integer"})
+		}
+
+		template, err := service.GetTemplate(ctx.Context(), ctx.Params("id"), version)
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to retrieve template")
+		}
+
+		return ctx.JSON(toTemplateResponse(template))
+	}
+}
+
+// ListTemplateVersionsHandler godoc
+// @Summary  List Template Versions
+// @Description  List every version of a template, oldest first
+// @Tags         templates
+// @Produce      json
+// @Param        id path string true "Template id"
+// @Success      200 {object} TemplateVersionsResponse "Versions of the template"
+// @Failure      404 {object} map[string]string "Template not found"
+// @Router       /templates/{id}/versions [get]
+func ListTemplateVersionsHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		versions, err := service.ListTemplateVersions(ctx.Context(), ctx.Params("id"))
+		if err != nil {
+			return messageChangeError(ctx, err, "Failed to list template versions")
+		}
+
+		response := TemplateVersionsResponse{Versions: make([]TemplateResponse, len(versions))}
+		for i, version := range versions {
+			response.Versions[i] = toTemplateResponse(version)
+		}
+
+		return ctx.JSON(response)
+	}
+}
+
+// UpdateTemplateHandler godoc
+// @Summary  Update Template
+// @Description  Store new content and variants as the next version of a template, variants of the previous version are kept unless variants are given. Messages waiting to be sent are rendered with it when they are sent, so it must render each of them.
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Template id"
+// @Param        template body request.UpdateTemplateRequest true "New content"
+// @Success      200 {object} TemplateResponse "New version of the template"
+// @Failure      400 {object} map[string]string "Invalid template"
+// @Failure      404 {object} map[string]string "Template not found"
+// @Failure      409 {object} map[string]string "Version does not render messages waiting to be sent"
+// @Router       /templates/{id} [put]
+func UpdateTemplateHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req request.UpdateTemplateRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+
+		template := &entity.TemplateEntity{Id: ctx.Params("id"), Content: req.Content, Locale: req.Locale, Variants: req.Variants}
+		if err := service.UpdateTemplate(ctx.Context(), template); err != nil {
+			return messageChangeError(ctx, err, "Failed to update template")
+		}
+
+		return ctx.JSON(toTemplateResponse(template))
+	}
+}
+
+// DeleteTemplateHandler godoc
+// @Summary  Delete Template
+// @Description  Delete a template with all its versions. Messages already sent from it keep their content.
+// @Tags         templates
+// @Param        id path string true "Template id"
+// @Success      204 "Template deleted"
+// @Failure      404 {object} map[string]string "Template not found"
+// @Failure      409 {object} map[string]string "Template is used by messages waiting to be sent"
+// @Router       /templates/{id} [delete]
+func DeleteTemplateHandler(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if err := service.DeleteTemplate(ctx.Context(), ctx.Params("id")); err != nil {
+			return messageChangeError(ctx, err, "Failed to delete template")
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func toTemplateResponse(template *entity.TemplateEntity) TemplateResponse {
+	variables := template.Variables
+	if variables == nil {
+		variables = []string{}
+	}
+
+	variants := template.Variants
+	if variants == nil {
+		variants = map[string]string{}
+	}
+
+	return TemplateResponse{
+		ID:        template.Id,
+		Name:      template.Name,
+		Version:   template.Version,
+		Content:   template.Content,
+		Locale:    template.Locale,
+		Variants:  variants,
+		Variables: variables,
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+}
@@ -59,8 +59,11 @@ func GetSentMessagesHandler(service *application.MessageSendService) fiber.Handl
 				Status:          string(message.Status),
 				CreatedAt:       message.CreatedAt,
 				UpdatedAt:       message.UpdatedAt,
+				Priority:        string(message.Priority),
+				SendAt:          message.SendAt,
 				SentAt:          message.SentAt,
 				RemoteMessageID: message.RemoteMessageId,
+				Provider:        message.Provider,
 			}
 		}
 
@@ -1,30 +1,82 @@
 package server
 
 import (
+	"io"
 	"message-scheduler/internal/application"
+	"message-scheduler/internal/infra/client/webhook"
 	"message-scheduler/internal/infra/server/api"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
 )
 
 type HealthResponse struct {
-	Status  string `json:"status" example:"ok"`
-	Message string `json:"message" example:"Message scheduler alive!"`
+	Status    string          `json:"status" example:"ok"`
+	Message   string          `json:"message" example:"Message scheduler alive!"`
+	Scheduler SchedulerHealth `json:"scheduler"`
+	Webhook   WebhookHealth   `json:"webhook"`
 }
 
+type SchedulerHealth struct {
+	Running        bool  `json:"running" example:"true"`
+	LeaderElection bool  `json:"leaderElection" example:"true"`
+	Leader         *bool `json:"leader,omitempty" example:"true"`
+}
+
+type WebhookHealth struct {
+	CircuitBreaker *CircuitBreakerHealth `json:"circuitBreaker,omitempty"`
+	// Providers is set when messages are routed over several providers.
+	Providers map[string]ProviderHealth `json:"providers,omitempty"`
+}
+
+type ProviderHealth struct {
+	CircuitBreaker *CircuitBreakerHealth `json:"circuitBreaker,omitempty"`
+}
+
+type CircuitBreakerHealth struct {
+	State               string `json:"state" example:"closed"`
+	ConsecutiveFailures int    `json:"consecutiveFailures" example:"0"`
+	OpenedAt            string `json:"openedAt,omitempty" example:"2025-01-01T12:00:00Z"`
+}
+
+const (
+	// bodyLimit leaves room for large /messages/batch payloads. NDJSON batches are not bound by it,
+	// they are read line by line and limited by their number of lines.
+	bodyLimit = 16 * 1024 * 1024
+	// streamBodyAfter is how much of a body is read before the handler is called, the rest is streamed.
+	streamBodyAfter = 64 * 1024
+)
+
 type AppServer struct {
 	app     *fiber.App
 	service *application.MessageSendService
 }
 
 func NewAppServer(service *application.MessageSendService) AppServer {
-	app := fiber.New()
+	app := fiber.New(fiber.Config{BodyLimit: streamBodyAfter, StreamRequestBody: true})
+	app.Use(limitBody(bodyLimit))
 
 	app.Post("/start-send-message", api.StartSendMessageHandler(service))
 	app.Post("/stop-message-sender", api.StopMessageSenderHandler(service))
 	app.Get("/sent-messages", api.GetSentMessagesHandler(service))
-	app.Get("/_monitoring/health", index)
+	app.Get("/messages", api.ListMessagesHandler(service))
+	app.Post("/messages", api.CreateMessageHandler(service))
+	app.Post("/messages/batch", api.CreateMessagesBatchHandler(service))
+	app.Get("/messages/:id", api.GetMessageHandler(service))
+	app.Delete("/messages/:id", api.CancelMessageHandler(service))
+	app.Patch("/messages/:id", api.UpdateMessageHandler(service))
+	app.Post("/templates", api.CreateTemplateHandler(service))
+	app.Get("/templates", api.ListTemplatesHandler(service))
+	app.Get("/templates/:id", api.GetTemplateHandler(service))
+	app.Put("/templates/:id", api.UpdateTemplateHandler(service))
+	app.Delete("/templates/:id", api.DeleteTemplateHandler(service))
+	app.Get("/templates/:id/versions", api.ListTemplateVersionsHandler(service))
+	app.Post("/callbacks/delivery", api.DeliveryReceiptHandler(service))
+	app.Post("/callbacks/delivery/:provider", api.DeliveryReceiptHandler(service))
+	app.Get("/admin/scheduler-settings", api.GetSchedulerSettingsHandler(service))
+	app.Patch("/admin/scheduler-settings", api.UpdateSchedulerSettingsHandler(service))
+	app.Get("/_monitoring/health", index(service))
 
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 
@@ -35,6 +87,30 @@ func NewAppServer(service *application.MessageSendService) AppServer {
 	return AppServer{app: app, service: service}
 }
 
+// limitBody reads the streamed body of every request up to limit before its handler runs, rejecting
+// larger ones with 413. NDJSON batches are left to stream into their handler.
+func limitBody(limit int) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		stream := ctx.Context().RequestBodyStream()
+		if stream == nil || api.IsNDJSONBatch(ctx) {
+			return ctx.Next()
+		}
+
+		body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read request body"})
+		}
+		if len(body) > limit {
+			// the rest of the body is still on the connection, it cannot carry another request
+			ctx.Context().SetConnectionClose()
+			return ctx.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "Request body too large"})
+		}
+
+		ctx.Request().SetBody(body)
+		return ctx.Next()
+	}
+}
+
 func (a AppServer) Start(port string) error {
 	return a.app.Listen(port)
 }
@@ -45,14 +121,50 @@ func (a AppServer) Shutdown() error {
 
 // HealthCheck godoc
 // @Summary  Health Check
-// @Description  Check if the message scheduler service is running
+// @Description  Check if the message scheduler service is running, whether this instance is the scheduler leader and the state of the webhook circuit breakers
 // @Tags         monitoring
 // @Produce      json
 // @Success      200 {object} HealthResponse "Service is healthy"
 // @Router       /_monitoring/health [get]
-func index(ctx *fiber.Ctx) error {
-	return ctx.JSON(HealthResponse{
-		Status:  "ok",
-		Message: "Message scheduler alive!",
-	})
+func index(service *application.MessageSendService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		schedulerStatus := service.SchedulerStatus()
+
+		schedulerHealth := SchedulerHealth{
+			Running:        schedulerStatus.Running,
+			LeaderElection: schedulerStatus.LeaderElection,
+		}
+		if schedulerStatus.LeaderElection {
+			schedulerHealth.Leader = &schedulerStatus.Leader
+		}
+
+		var webhookHealth WebhookHealth
+		if breakerStatus, ok := service.CircuitBreakerStatus(); ok {
+			webhookHealth.CircuitBreaker = circuitBreakerHealth(breakerStatus)
+		}
+		if breakerStatuses, ok := service.ProviderBreakerStatuses(); ok {
+			webhookHealth.Providers = make(map[string]ProviderHealth)
+			for provider, breakerStatus := range breakerStatuses {
+				webhookHealth.Providers[provider] = ProviderHealth{CircuitBreaker: circuitBreakerHealth(breakerStatus)}
+			}
+		}
+
+		return ctx.JSON(HealthResponse{
+			Status:    "ok",
+			Message:   "Message scheduler alive!",
+			Scheduler: schedulerHealth,
+			Webhook:   webhookHealth,
+		})
+	}
+}
+
+func circuitBreakerHealth(breakerStatus webhook.BreakerStatus) *CircuitBreakerHealth {
+	health := &CircuitBreakerHealth{
+		State:               string(breakerStatus.State),
+		ConsecutiveFailures: breakerStatus.ConsecutiveFailures,
+	}
+	if !breakerStatus.OpenedAt.IsZero() {
+		health.OpenedAt = breakerStatus.OpenedAt.UTC().Format(time.RFC3339)
+	}
+	return health
 }
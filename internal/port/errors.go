@@ -46,3 +46,53 @@ func (sc DependencyError) Error() string {
 func (sc DependencyError) Unwrap() error {
 	return sc.WrappedErr
 }
+
+type NotFoundError struct { // UnWrappable
+	Msg        string
+	WrappedErr error
+}
+
+func (sc NotFoundError) Error() string {
+	if sc.WrappedErr != nil {
+		return fmt.Sprintf("%s, %s", sc.Msg, sc.WrappedErr.Error())
+	}
+	return sc.Msg
+}
+
+func (sc NotFoundError) Unwrap() error {
+	return sc.WrappedErr
+}
+
+// UnauthorizedError means the caller could not prove who it is.
+type UnauthorizedError struct { // UnWrappable
+	Msg        string
+	WrappedErr error
+}
+
+func (sc UnauthorizedError) Error() string {
+	if sc.WrappedErr != nil {
+		return fmt.Sprintf("%s, %s", sc.Msg, sc.WrappedErr.Error())
+	}
+	return sc.Msg
+}
+
+func (sc UnauthorizedError) Unwrap() error {
+	return sc.WrappedErr
+}
+
+// ConflictError means the change was valid but the resource is no longer in a state that allows it.
+type ConflictError struct { // UnWrappable
+	Msg        string
+	WrappedErr error
+}
+
+func (sc ConflictError) Error() string {
+	if sc.WrappedErr != nil {
+		return fmt.Sprintf("%s, %s", sc.Msg, sc.WrappedErr.Error())
+	}
+	return sc.Msg
+}
+
+func (sc ConflictError) Unwrap() error {
+	return sc.WrappedErr
+}
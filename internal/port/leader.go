@@ -0,0 +1,15 @@
+package port
+
+import "context"
+
+// LeaderLock is a cluster-wide mutex used to elect the single instance that runs scheduled jobs.
+type LeaderLock interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	// Check returns an error once the lock can no longer be guaranteed, e.g. its connection broke.
+	Check(ctx context.Context) error
+	Release(ctx context.Context) error
+}
+
+type LeaderAware interface {
+	IsLeader() bool
+}
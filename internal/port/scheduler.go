@@ -10,6 +10,14 @@ type Job interface {
 	Name() string
 }
 
+// DynamicJob is a Job whose interval and timeout may change while it is scheduled. The scheduler
+// reads both again around every run, so changes take effect on the next tick.
+type DynamicJob interface {
+	Job
+	Interval() time.Duration
+	Timeout() time.Duration
+}
+
 type Scheduler interface {
 	ScheduleJob(job Job, interval time.Duration)
 	Start(ctx context.Context)
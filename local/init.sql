@@ -1,14 +1,211 @@
+-- Message templates. Every change adds a row to template_versions, templates.version points at
+-- the current one.
+CREATE TABLE templates (
+                          id SERIAL PRIMARY KEY,
+                          name VARCHAR(100) NOT NULL UNIQUE,
+                          version INT NOT NULL DEFAULT 1,
+                          created_at TIMESTAMPTZ DEFAULT now(),
+                          updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE TABLE template_versions (
+                          id SERIAL PRIMARY KEY,
+                          template_id INT NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+                          version INT NOT NULL,
+                          content TEXT NOT NULL,
+                          locale VARCHAR(35) NULL,
+                          variants JSONB NULL,
+                          variables TEXT[] NOT NULL DEFAULT '{}',
+                          created_at TIMESTAMPTZ DEFAULT now(),
+                          UNIQUE (template_id, version)
+);
+
 CREATE TABLE messages (
                           id SERIAL PRIMARY KEY,
                           phone VARCHAR(30) NOT NULL,
                           content TEXT NOT NULL CHECK (char_length(content) <= 100),
                           status VARCHAR(20) NOT NULL DEFAULT 'unsent',
-                          created_at TIMESTAMP DEFAULT now(),
-                          updated_at TIMESTAMP DEFAULT now(),
-                          sent_at TIMESTAMP NULL,
-                          remote_message_id TEXT NULL
+                          priority VARCHAR(20) NOT NULL DEFAULT 'normal',
+                          tags TEXT[] NOT NULL DEFAULT '{}',
+                          created_at TIMESTAMPTZ DEFAULT now(),
+                          updated_at TIMESTAMPTZ DEFAULT now(),
+                          send_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          sent_at TIMESTAMPTZ NULL,
+                          remote_message_id TEXT NULL,
+                          provider VARCHAR(50) NULL,
+                          attempts INT NOT NULL DEFAULT 0,
+                          last_error TEXT NULL,
+                          next_attempt_at TIMESTAMPTZ NULL,
+                          lease_owner VARCHAR(100) NULL,
+                          lease_expires_at TIMESTAMPTZ NULL,
+                          delivery_reported_at TIMESTAMPTZ NULL,
+                          delivery_error TEXT NULL,
+                          version INT NOT NULL DEFAULT 1,
+                          template_id INT NULL REFERENCES templates (id) ON DELETE SET NULL,
+                          template_version INT NULL,
+                          variables JSONB NULL,
+                          locale VARCHAR(35) NULL,
+                          -- critical (0) to bulk (3), the order messages are claimed in before aging
+                          priority_rank SMALLINT GENERATED ALWAYS AS (
+                              CASE priority WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END
+                          ) STORED,
+                          -- when a waiting message becomes due, NULL once it no longer waits
+                          due_at TIMESTAMPTZ GENERATED ALWAYS AS (
+                              CASE WHEN status IN ('unsent', 'retrying') THEN GREATEST(send_at, next_attempt_at) END
+                          ) STORED
+);
+
+CREATE INDEX messages_status_next_attempt_at_idx ON messages (status, next_attempt_at);
+CREATE INDEX messages_status_send_at_idx ON messages (status, send_at);
+-- the claim reads due messages per priority in due order from here
+CREATE INDEX messages_claim_idx ON messages (priority_rank, due_at, id) WHERE due_at IS NOT NULL;
+CREATE INDEX messages_created_at_id_idx ON messages (created_at DESC, id DESC);
+CREATE INDEX messages_phone_idx ON messages (phone);
+CREATE INDEX messages_provider_remote_message_id_idx ON messages (provider, remote_message_id);
+CREATE INDEX messages_tags_idx ON messages USING GIN (tags);
+CREATE INDEX messages_template_id_idx ON messages (template_id) WHERE template_id IS NOT NULL;
+
+CREATE TABLE message_history (
+                          id SERIAL PRIMARY KEY,
+                          message_id INT NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+                          status VARCHAR(20) NOT NULL,
+                          priority VARCHAR(20) NOT NULL,
+                          content TEXT NOT NULL,
+                          send_at TIMESTAMPTZ NOT NULL,
+                          attempts INT NOT NULL,
+                          last_error TEXT NULL,
+                          remote_message_id TEXT NULL,
+                          version INT NOT NULL,
+                          recorded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX message_history_message_id_idx ON message_history (message_id, id);
+
+-- Snapshots a message whenever its status, content, priority or send time changes, so every
+-- writer, including plain SQL, ends up in the history.
+CREATE FUNCTION record_message_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.status IS NOT DISTINCT FROM OLD.status
+        AND NEW.content IS NOT DISTINCT FROM OLD.content
+        AND NEW.priority IS NOT DISTINCT FROM OLD.priority
+        AND NEW.send_at IS NOT DISTINCT FROM OLD.send_at THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO message_history (message_id, status, priority, content, send_at, attempts, last_error, remote_message_id, version)
+    VALUES (NEW.id, NEW.status, NEW.priority, NEW.content, NEW.send_at, NEW.attempts, NEW.last_error, NEW.remote_message_id, NEW.version);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER messages_history
+    AFTER INSERT OR UPDATE ON messages
+    FOR EACH ROW EXECUTE FUNCTION record_message_history();
+
+
+-- Requests made to every provider per UTC day, shared by all instances for the daily cap.
+CREATE TABLE provider_daily_usage (
+                          provider VARCHAR(50) NOT NULL,
+                          day DATE NOT NULL,
+                          sent INT NOT NULL DEFAULT 0,
+                          PRIMARY KEY (provider, day)
+);
+
+-- Scheduler settings changed through the API. The single row is shared by all replicas, so the
+-- elected leader picks up a change whichever replica handled it.
+CREATE TABLE scheduler_settings (
+                          id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+                          batch_size INT NOT NULL,
+                          interval_ms BIGINT NOT NULL,
+                          job_timeout_ms BIGINT NOT NULL,
+                          concurrency INT NOT NULL,
+                          preserve_recipient_order BOOLEAN NOT NULL DEFAULT false,
+                          priority_aging_ms BIGINT NOT NULL,
+                          updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+
+-- One row per delivery attempt, written before the webhook call. A crash between the call and
+-- saving the message leaves the row behind, so the retry reuses its idempotency key.
+CREATE TABLE send_attempts (
+                          id SERIAL PRIMARY KEY,
+                          message_id INT NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+                          attempt INT NOT NULL,
+                          idempotency_key VARCHAR(100) NOT NULL UNIQUE,
+                          status VARCHAR(20) NOT NULL DEFAULT 'pending',
+                          remote_message_id TEXT NULL,
+                          provider VARCHAR(50) NULL,
+                          error TEXT NULL,
+                          started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          finished_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX send_attempts_message_id_idx ON send_attempts (message_id, attempt);
+
+
+CREATE TABLE outbox_events (
+                          id BIGSERIAL PRIMARY KEY,
+                          event_type VARCHAR(50) NOT NULL,
+                          message_id INT NOT NULL,
+                          payload JSONB NOT NULL,
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          attempts INT NOT NULL DEFAULT 0,
+                          last_error TEXT NULL,
+                          lease_owner VARCHAR(100) NULL,
+                          lease_expires_at TIMESTAMPTZ NULL,
+                          published_at TIMESTAMPTZ NULL
 );
 
+CREATE INDEX outbox_events_unpublished_idx ON outbox_events (id) WHERE published_at IS NULL;
+CREATE INDEX outbox_events_unpublished_message_id_idx ON outbox_events (message_id, id) WHERE published_at IS NULL;
+CREATE INDEX outbox_events_published_at_idx ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+
+-- Queues an event for the outbox relay whenever a message is sent, fails for good or gets a delivery
+-- receipt. Being a trigger, the event commits or rolls back together with the status change.
+CREATE FUNCTION record_message_event() RETURNS TRIGGER AS $$
+DECLARE
+    new_event_type VARCHAR(50);
+BEGIN
+    IF NEW.status IS NOT DISTINCT FROM OLD.status THEN
+        RETURN NEW;
+    END IF;
+
+    new_event_type := CASE NEW.status
+        WHEN 'sent' THEN 'message.sent'
+        WHEN 'delivered' THEN 'message.delivered'
+        WHEN 'failed' THEN 'message.failed'
+        WHEN 'dead' THEN 'message.failed'
+        WHEN 'undelivered' THEN 'message.failed'
+    END;
+    IF new_event_type IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO outbox_events (event_type, message_id, payload)
+    VALUES (new_event_type, NEW.id, jsonb_build_object(
+        'messageId', NEW.id::text,
+        'status', NEW.status,
+        'previousStatus', OLD.status,
+        'phone', NEW.phone,
+        'priority', NEW.priority,
+        'tags', to_jsonb(NEW.tags),
+        'attempts', NEW.attempts,
+        'remoteMessageId', NEW.remote_message_id,
+        'provider', NEW.provider,
+        'sentAt', NEW.sent_at,
+        'lastError', NEW.last_error,
+        'deliveryError', NEW.delivery_error,
+        'version', NEW.version
+    ));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER messages_outbox
+    AFTER UPDATE OF status ON messages
+    FOR EACH ROW EXECUTE FUNCTION record_message_event();
+
 
 INSERT INTO messages (phone, content)
 VALUES
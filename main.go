@@ -9,15 +9,23 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"message-scheduler/config"
 	_ "message-scheduler/docs"
 	"message-scheduler/internal/application"
+	"message-scheduler/internal/domain/types/priority"
+	"message-scheduler/internal/infra/client/sink"
 	"message-scheduler/internal/infra/client/webhook"
 	"message-scheduler/internal/infra/database"
 	"message-scheduler/internal/infra/repository"
 	"message-scheduler/internal/infra/scheduler"
 	"message-scheduler/internal/infra/server"
+	"message-scheduler/internal/port"
 	"message-scheduler/log"
+	"message-scheduler/signature"
 	"os"
 	"os/signal"
 	"syscall"
@@ -32,14 +40,74 @@ func main() {
 	db := database.NewPostgresDB(cfg.Postgres)
 
 	messagesRepo := repository.NewMessagesRepository(db)
+	sendAttemptsRepo := repository.NewSendAttemptsRepository(db)
+	templatesRepo := repository.NewTemplatesRepository(db)
 
-	webhookClient := webhook.NewWebhookClient(cfg.WebhookConfig.Host, time.Duration(cfg.WebhookConfig.Timeout)*time.Millisecond)
+	webhookClient, err := webhookProviders(cfg, repository.NewProviderUsageRepository(db))
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("Invalid webhook configuration")
+	}
+
+	verifiers, err := callbackVerifiers(cfg)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("Invalid callback configuration")
+	}
+
+	var messageScheduler port.Scheduler = scheduler.NewSimpleScheduler()
+	if cfg.Scheduler.LeaderElection.Enabled {
+		leaderLock := database.NewAdvisoryLock(db, cfg.Scheduler.LeaderElection.LockKey)
+		messageScheduler = scheduler.NewLeaderElectedScheduler(messageScheduler, leaderLock,
+			time.Duration(cfg.Scheduler.LeaderElection.RetryInterval)*time.Millisecond)
+	}
+
+	retryPolicy := application.RetryPolicy{
+		MaxAttempts: cfg.Retry.MaxAttempts,
+		BaseDelay:   time.Duration(cfg.Retry.BaseDelay) * time.Millisecond,
+		MaxDelay:    time.Duration(cfg.Retry.MaxDelay) * time.Millisecond,
+		Multiplier:  cfg.Retry.Multiplier,
+		Jitter:      *cfg.Retry.Jitter,
+	}
+
+	leaseDuration := time.Duration(cfg.Scheduler.LeaseDuration) * time.Millisecond
+	schedulerSettings := application.SchedulerSettings{
+		BatchSize:              cfg.Scheduler.BatchSize,
+		Interval:               time.Duration(cfg.Scheduler.Interval) * time.Millisecond,
+		JobTimeout:             time.Duration(cfg.Scheduler.JobTimeout) * time.Millisecond,
+		Concurrency:            cfg.Scheduler.Concurrency,
+		PreserveRecipientOrder: cfg.Scheduler.PreserveRecipientOrder,
+		PriorityAging:          time.Duration(cfg.Scheduler.PriorityAging) * time.Millisecond,
+	}
+	if err := application.ValidateSchedulerSettings(schedulerSettings, leaseDuration); err != nil {
+		log.Logger.Fatal().Err(err).Msg("Invalid scheduler configuration")
+	}
 
-	messageScheduler := scheduler.NewSimpleScheduler()
+	serviceOptions := []application.Option{
+		application.WithRetryPolicy(retryPolicy),
+		application.WithLease("", leaseDuration,
+			time.Duration(cfg.Scheduler.LeaseReapInterval)*time.Millisecond),
+		application.WithSchedulerSettings(schedulerSettings),
+		application.WithSettingsStore(repository.NewSchedulerSettingsRepository(db)),
+		application.WithSendAttempts(sendAttemptsRepo),
+		application.WithTemplates(templatesRepo, cfg.Templates.DefaultLocale),
+		application.WithCallbackVerifiers(verifiers),
+	}
+	if cfg.Outbox.Enabled {
+		eventSink, err := outboxSink(cfg.Outbox)
+		if err != nil {
+			log.Logger.Fatal().Err(err).Msg("Invalid outbox configuration")
+		}
+		serviceOptions = append(serviceOptions, application.WithOutbox(repository.NewOutboxRepository(db), eventSink, scheduler.NewSimpleScheduler(), application.OutboxSettings{
+			BatchSize:  cfg.Outbox.BatchSize,
+			Interval:   time.Duration(cfg.Outbox.Interval) * time.Millisecond,
+			RetryDelay: time.Duration(cfg.Outbox.RetryDelay) * time.Millisecond,
+			Retention:  time.Duration(cfg.Outbox.Retention) * time.Millisecond,
+		}))
+	}
 
-	messageService := application.NewMessageSendService(webhookClient, messagesRepo, messageScheduler)
+	messageService := application.NewMessageSendService(webhookClient, messagesRepo, messageScheduler, serviceOptions...)
 	
 	messageService.StartScheduler(context.Background())
+	messageService.StartOutboxRelay(context.Background())
 
 	appServer := server.NewAppServer(messageService)
 
@@ -62,9 +130,226 @@ func main() {
 		log.Logger.Error().Err(err).Msg("Error stopping scheduler")
 	}
 
+	if err := messageService.StopOutboxRelay(); err != nil {
+		log.Logger.Error().Err(err).Msg("Error stopping outbox relay")
+	}
+
 	if err := appServer.Shutdown(); err != nil {
 		log.Logger.Error().Err(err).Msg("Error stopping server")
 	}
 
 	log.Logger.Info().Msg("Message Scheduler stopped successfully")
 }
+
+// newWebhookClient creates the client of a single provider, wrapped in its rate limiter and circuit
+// breaker. The daily cap is counted in usage so it holds across replicas.
+func newWebhookClient(name string, cfg config.WebhookConfiguration, usage webhook.DailyCounter) (webhook.WebhookClient, error) {
+	webhookOptions, err := webhookClientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	webhookOptions = append(webhookOptions, webhook.WithProviderName(name))
+
+	var webhookClient webhook.WebhookClient = webhook.NewWebhookClient(cfg.Host, time.Duration(cfg.Timeout)*time.Millisecond, webhookOptions...)
+	if rateLimit := cfg.RateLimit; rateLimit.Rate > 0 || rateLimit.DailyCap > 0 {
+		webhookClient = webhook.NewRateLimitedClient(webhookClient, webhook.RateLimit{
+			Rate:     rateLimit.Rate,
+			Burst:    rateLimit.Burst,
+			DailyCap: rateLimit.DailyCap,
+			Counter:  usage,
+			Provider: name,
+		})
+	}
+	if breaker := cfg.CircuitBreaker; breaker.Enabled {
+		webhookClient = webhook.NewCircuitBreakerClient(webhookClient, webhook.CircuitBreakerSettings{
+			FailureThreshold: breaker.FailureThreshold,
+			CoolDown:         time.Duration(breaker.CoolDown) * time.Millisecond,
+		})
+	}
+
+	return webhookClient, nil
+}
+
+// webhookProviders creates a client per provider, puts the ones with a failover list, which need their
+// circuit breaker enabled, in front of their chain and, when there is more than one provider, a router that picks one for every message.
+func webhookProviders(cfg config.AppConfig, usage webhook.DailyCounter) (webhook.WebhookClient, error) {
+	configs := map[string]config.WebhookConfiguration{webhook.DefaultProvider: cfg.WebhookConfig}
+	for name, providerCfg := range cfg.Providers {
+		if name == webhook.DefaultProvider {
+			return nil, fmt.Errorf("provider name %q is reserved for the webhook settings", name)
+		}
+		configs[name] = providerCfg
+	}
+
+	clients := make(map[string]webhook.WebhookClient, len(configs))
+	for name, providerCfg := range configs {
+		client, err := newWebhookClient(name, providerCfg, usage)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", name, err)
+		}
+		clients[name] = client
+	}
+
+	providers := make(map[string]webhook.WebhookClient, len(clients))
+	for name, client := range clients {
+		providers[name] = client
+		if len(configs[name].Failover) == 0 {
+			continue
+		}
+		// only an open breaker fails over after timeouts and 5xx, without one the provider never would
+		if !configs[name].CircuitBreaker.Enabled {
+			return nil, fmt.Errorf("provider %s: failover requires circuitBreaker.enabled", name)
+		}
+
+		fallbacks := make([]webhook.NamedClient, len(configs[name].Failover))
+		for i, fallback := range configs[name].Failover {
+			fallbackClient, ok := clients[fallback]
+			if !ok || fallback == name {
+				return nil, fmt.Errorf("provider %s: invalid failover provider %q", name, fallback)
+			}
+			fallbacks[i] = webhook.NamedClient{Name: fallback, Client: fallbackClient}
+		}
+		providers[name] = webhook.NewFailoverClient(webhook.NamedClient{Name: name, Client: client}, fallbacks...)
+	}
+
+	if len(providers) == 1 && len(cfg.Routing.Rules) == 0 {
+		return providers[webhook.DefaultProvider], nil
+	}
+
+	rules := make([]webhook.Rule, len(cfg.Routing.Rules))
+	for i, rule := range cfg.Routing.Rules {
+		rules[i] = webhook.Rule{
+			Provider:      rule.Provider,
+			PhonePrefixes: rule.PhonePrefixes,
+			Tags:          rule.Tags,
+		}
+		for _, p := range rule.Priorities {
+			rules[i].Priorities = append(rules[i].Priorities, priority.MessagePriority(p))
+		}
+	}
+
+	fallback := cfg.Routing.Default
+	if fallback == "" {
+		fallback = webhook.DefaultProvider
+	}
+
+	return webhook.NewRouter(providers, rules, fallback)
+}
+
+// callbackVerifiers creates a verifier for the delivery callbacks of every provider with callback secrets.
+func callbackVerifiers(cfg config.AppConfig) (map[string]*signature.Verifier, error) {
+	configs := map[string]config.CallbackConfig{webhook.DefaultProvider: cfg.WebhookConfig.Callbacks}
+	for name, providerCfg := range cfg.Providers {
+		configs[name] = providerCfg.Callbacks
+	}
+
+	verifiers := make(map[string]*signature.Verifier, len(configs))
+	for name, callbackCfg := range configs {
+		if len(callbackCfg.Secrets) == 0 {
+			continue
+		}
+
+		verifier, err := signature.NewVerifier(time.Duration(callbackCfg.Tolerance)*time.Millisecond, callbackCfg.Secrets...)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", name, err)
+		}
+		verifiers[name] = verifier
+	}
+
+	return verifiers, nil
+}
+
+// webhookClientOptions turns the signing, authentication, TLS and request settings into client options.
+func webhookClientOptions(cfg config.WebhookConfiguration) ([]webhook.ClientOption, error) {
+	var options []webhook.ClientOption
+
+	if len(cfg.Signing.Secrets) > 0 {
+		signer, err := signature.NewSigner(cfg.Signing.Secrets...)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, webhook.WithSigner(signer))
+	}
+
+	switch auth := cfg.Auth; auth.Type {
+	case config.AuthNone:
+	case config.AuthBearer:
+		options = append(options, webhook.WithAuthenticator(webhook.BearerToken(auth.Token)))
+	case config.AuthBasic:
+		options = append(options, webhook.WithAuthenticator(webhook.BasicAuth(auth.Username, auth.Password)))
+	case config.AuthAPIKey:
+		options = append(options, webhook.WithAuthenticator(webhook.APIKey(auth.Header, auth.APIKey)))
+	case config.AuthOAuth2:
+		if auth.OAuth2.TokenURL == "" {
+			return nil, errors.New("webhook.auth.oauth2.tokenUrl is required")
+		}
+		options = append(options, webhook.WithAuthenticator(webhook.NewOAuth2ClientCredentials(webhook.OAuth2Settings{
+			TokenURL:     auth.OAuth2.TokenURL,
+			ClientID:     auth.OAuth2.ClientID,
+			ClientSecret: auth.OAuth2.ClientSecret,
+			Scopes:       auth.OAuth2.Scopes,
+		}, time.Duration(cfg.Timeout)*time.Millisecond)))
+	default:
+		return nil, fmt.Errorf("unknown webhook.auth.type %q", auth.Type)
+	}
+
+	if cfg.TLS.CertFile != "" || cfg.TLS.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load webhook client certificate: %w", err)
+		}
+		options = append(options, webhook.WithClientCertificate(certificate))
+	}
+
+	if cfg.TLS.CAFile != "" {
+		pem, err := os.ReadFile(cfg.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLS.CAFile)
+		}
+		options = append(options, webhook.WithRootCAs(pool))
+	}
+
+	if request := cfg.Request; request.Method != "" || len(request.Headers) > 0 || request.Body != "" || request.MessageIDPath != "" {
+		requestTemplate, err := webhook.NewRequestTemplate(webhook.RequestTemplateSettings{
+			Method:        request.Method,
+			Headers:       request.Headers,
+			Body:          request.Body,
+			MessageIDPath: request.MessageIDPath,
+		})
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, webhook.WithRequestTemplate(requestTemplate))
+	}
+
+	return options, nil
+}
+
+// outboxSink creates the sink the outbox relay publishes to.
+func outboxSink(cfg config.OutboxConfig) (sink.EventSink, error) {
+	switch cfg.Sink {
+	case config.SinkHTTP:
+		if cfg.HTTP.URL == "" {
+			return nil, errors.New("outbox.http.url is required")
+		}
+		var signer *signature.Signer
+		if len(cfg.HTTP.Signing.Secrets) > 0 {
+			var err error
+			if signer, err = signature.NewSigner(cfg.HTTP.Signing.Secrets...); err != nil {
+				return nil, err
+			}
+		}
+		return sink.NewHTTPSink(cfg.HTTP.URL, time.Duration(cfg.HTTP.Timeout)*time.Millisecond, signer), nil
+	case config.SinkFile:
+		if cfg.File.Path == "" {
+			return nil, errors.New("outbox.file.path is required")
+		}
+		return sink.NewFileSink(cfg.File.Path), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox.sink %q", cfg.Sink)
+	}
+}
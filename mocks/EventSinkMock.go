@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "message-scheduler/internal/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventSinkMock is an autogenerated mock type for the EventSink type
+type EventSinkMock struct {
+	mock.Mock
+}
+
+type EventSinkMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *EventSinkMock) EXPECT() *EventSinkMock_Expecter {
+	return &EventSinkMock_Expecter{mock: &_m.Mock}
+}
+
+// Publish provides a mock function with given fields: ctx, events
+func (_m *EventSinkMock) Publish(ctx context.Context, events []*entity.OutboxEventEntity) error {
+	ret := _m.Called(ctx, events)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.OutboxEventEntity) error); ok {
+		r0 = rf(ctx, events)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EventSinkMock_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type EventSinkMock_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - events []*entity.OutboxEventEntity
+func (_e *EventSinkMock_Expecter) Publish(ctx interface{}, events interface{}) *EventSinkMock_Publish_Call {
+	return &EventSinkMock_Publish_Call{Call: _e.mock.On("Publish", ctx, events)}
+}
+
+func (_c *EventSinkMock_Publish_Call) Run(run func(ctx context.Context, events []*entity.OutboxEventEntity)) *EventSinkMock_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*entity.OutboxEventEntity))
+	})
+	return _c
+}
+
+func (_c *EventSinkMock_Publish_Call) Return(_a0 error) *EventSinkMock_Publish_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *EventSinkMock_Publish_Call) RunAndReturn(run func(context.Context, []*entity.OutboxEventEntity) error) *EventSinkMock_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewEventSinkMock creates a new instance of EventSinkMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventSinkMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventSinkMock {
+	mock := &EventSinkMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
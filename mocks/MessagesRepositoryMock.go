@@ -7,6 +7,8 @@ import (
 	entity "message-scheduler/internal/domain/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repository "message-scheduler/internal/infra/repository"
 )
 
 // MessagesRepositoryMock is an autogenerated mock type for the MessagesRepository type
@@ -22,6 +24,290 @@ func (_m *MessagesRepositoryMock) EXPECT() *MessagesRepositoryMock_Expecter {
 	return &MessagesRepositoryMock_Expecter{mock: &_m.Mock}
 }
 
+// ClaimMessages provides a mock function with given fields: ctx, claim
+func (_m *MessagesRepositoryMock) ClaimMessages(ctx context.Context, claim repository.ClaimOptions) ([]*entity.MessagesEntity, error) {
+	ret := _m.Called(ctx, claim)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimMessages")
+	}
+
+	var r0 []*entity.MessagesEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ClaimOptions) ([]*entity.MessagesEntity, error)); ok {
+		return rf(ctx, claim)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ClaimOptions) []*entity.MessagesEntity); ok {
+		r0 = rf(ctx, claim)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.MessagesEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.ClaimOptions) error); ok {
+		r1 = rf(ctx, claim)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_ClaimMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimMessages'
+type MessagesRepositoryMock_ClaimMessages_Call struct {
+	*mock.Call
+}
+
+// ClaimMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - claim repository.ClaimOptions
+func (_e *MessagesRepositoryMock_Expecter) ClaimMessages(ctx interface{}, claim interface{}) *MessagesRepositoryMock_ClaimMessages_Call {
+	return &MessagesRepositoryMock_ClaimMessages_Call{Call: _e.mock.On("ClaimMessages", ctx, claim)}
+}
+
+func (_c *MessagesRepositoryMock_ClaimMessages_Call) Run(run func(ctx context.Context, claim repository.ClaimOptions)) *MessagesRepositoryMock_ClaimMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.ClaimOptions))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_ClaimMessages_Call) Return(_a0 []*entity.MessagesEntity, _a1 error) *MessagesRepositoryMock_ClaimMessages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_ClaimMessages_Call) RunAndReturn(run func(context.Context, repository.ClaimOptions) ([]*entity.MessagesEntity, error)) *MessagesRepositoryMock_ClaimMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, message
+func (_m *MessagesRepositoryMock) Create(ctx context.Context, message *entity.MessagesEntity) error {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MessagesRepositoryMock_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MessagesRepositoryMock_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *entity.MessagesEntity
+func (_e *MessagesRepositoryMock_Expecter) Create(ctx interface{}, message interface{}) *MessagesRepositoryMock_Create_Call {
+	return &MessagesRepositoryMock_Create_Call{Call: _e.mock.On("Create", ctx, message)}
+}
+
+func (_c *MessagesRepositoryMock_Create_Call) Run(run func(ctx context.Context, message *entity.MessagesEntity)) *MessagesRepositoryMock_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.MessagesEntity))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_Create_Call) Return(_a0 error) *MessagesRepositoryMock_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_Create_Call) RunAndReturn(run func(context.Context, *entity.MessagesEntity) error) *MessagesRepositoryMock_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessage provides a mock function with given fields: ctx, id
+func (_m *MessagesRepositoryMock) GetMessage(ctx context.Context, id string) (*entity.MessagesEntity, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessage")
+	}
+
+	var r0 *entity.MessagesEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.MessagesEntity, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.MessagesEntity); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.MessagesEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_GetMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessage'
+type MessagesRepositoryMock_GetMessage_Call struct {
+	*mock.Call
+}
+
+// GetMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MessagesRepositoryMock_Expecter) GetMessage(ctx interface{}, id interface{}) *MessagesRepositoryMock_GetMessage_Call {
+	return &MessagesRepositoryMock_GetMessage_Call{Call: _e.mock.On("GetMessage", ctx, id)}
+}
+
+func (_c *MessagesRepositoryMock_GetMessage_Call) Run(run func(ctx context.Context, id string)) *MessagesRepositoryMock_GetMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_GetMessage_Call) Return(_a0 *entity.MessagesEntity, _a1 error) *MessagesRepositoryMock_GetMessage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_GetMessage_Call) RunAndReturn(run func(context.Context, string) (*entity.MessagesEntity, error)) *MessagesRepositoryMock_GetMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessageByRemoteID provides a mock function with given fields: ctx, provider, remoteMessageID
+func (_m *MessagesRepositoryMock) GetMessageByRemoteID(ctx context.Context, provider string, remoteMessageID string) (*entity.MessagesEntity, error) {
+	ret := _m.Called(ctx, provider, remoteMessageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessageByRemoteID")
+	}
+
+	var r0 *entity.MessagesEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.MessagesEntity, error)); ok {
+		return rf(ctx, provider, remoteMessageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.MessagesEntity); ok {
+		r0 = rf(ctx, provider, remoteMessageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.MessagesEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, remoteMessageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_GetMessageByRemoteID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessageByRemoteID'
+type MessagesRepositoryMock_GetMessageByRemoteID_Call struct {
+	*mock.Call
+}
+
+// GetMessageByRemoteID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - remoteMessageID string
+func (_e *MessagesRepositoryMock_Expecter) GetMessageByRemoteID(ctx interface{}, provider interface{}, remoteMessageID interface{}) *MessagesRepositoryMock_GetMessageByRemoteID_Call {
+	return &MessagesRepositoryMock_GetMessageByRemoteID_Call{Call: _e.mock.On("GetMessageByRemoteID", ctx, provider, remoteMessageID)}
+}
+
+func (_c *MessagesRepositoryMock_GetMessageByRemoteID_Call) Run(run func(ctx context.Context, provider string, remoteMessageID string)) *MessagesRepositoryMock_GetMessageByRemoteID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_GetMessageByRemoteID_Call) Return(_a0 *entity.MessagesEntity, _a1 error) *MessagesRepositoryMock_GetMessageByRemoteID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_GetMessageByRemoteID_Call) RunAndReturn(run func(context.Context, string, string) (*entity.MessagesEntity, error)) *MessagesRepositoryMock_GetMessageByRemoteID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessageHistory provides a mock function with given fields: ctx, id
+func (_m *MessagesRepositoryMock) GetMessageHistory(ctx context.Context, id string) ([]*entity.MessageHistoryEntity, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessageHistory")
+	}
+
+	var r0 []*entity.MessageHistoryEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.MessageHistoryEntity, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.MessageHistoryEntity); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.MessageHistoryEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_GetMessageHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessageHistory'
+type MessagesRepositoryMock_GetMessageHistory_Call struct {
+	*mock.Call
+}
+
+// GetMessageHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MessagesRepositoryMock_Expecter) GetMessageHistory(ctx interface{}, id interface{}) *MessagesRepositoryMock_GetMessageHistory_Call {
+	return &MessagesRepositoryMock_GetMessageHistory_Call{Call: _e.mock.On("GetMessageHistory", ctx, id)}
+}
+
+func (_c *MessagesRepositoryMock_GetMessageHistory_Call) Run(run func(ctx context.Context, id string)) *MessagesRepositoryMock_GetMessageHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_GetMessageHistory_Call) Return(_a0 []*entity.MessageHistoryEntity, _a1 error) *MessagesRepositoryMock_GetMessageHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_GetMessageHistory_Call) RunAndReturn(run func(context.Context, string) ([]*entity.MessageHistoryEntity, error)) *MessagesRepositoryMock_GetMessageHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetSentMessages provides a mock function with given fields: ctx, recordLimit
 func (_m *MessagesRepositoryMock) GetSentMessages(ctx context.Context, recordLimit int) ([]*entity.MessagesEntity, error) {
 	ret := _m.Called(ctx, recordLimit)
@@ -140,17 +426,130 @@ func (_c *MessagesRepositoryMock_GetUnsentMessages_Call) RunAndReturn(run func(c
 	return _c
 }
 
-// Save provides a mock function with given fields: ctx, message
-func (_m *MessagesRepositoryMock) Save(ctx context.Context, message *entity.MessagesEntity) error {
+// RecordDelivery provides a mock function with given fields: ctx, message
+func (_m *MessagesRepositoryMock) RecordDelivery(ctx context.Context, message *entity.MessagesEntity) (bool, error) {
 	ret := _m.Called(ctx, message)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Save")
+		panic("no return value specified for RecordDelivery")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity) error); ok {
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity) (bool, error)); ok {
+		return rf(ctx, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity) bool); ok {
 		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.MessagesEntity) error); ok {
+		r1 = rf(ctx, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_RecordDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordDelivery'
+type MessagesRepositoryMock_RecordDelivery_Call struct {
+	*mock.Call
+}
+
+// RecordDelivery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *entity.MessagesEntity
+func (_e *MessagesRepositoryMock_Expecter) RecordDelivery(ctx interface{}, message interface{}) *MessagesRepositoryMock_RecordDelivery_Call {
+	return &MessagesRepositoryMock_RecordDelivery_Call{Call: _e.mock.On("RecordDelivery", ctx, message)}
+}
+
+func (_c *MessagesRepositoryMock_RecordDelivery_Call) Run(run func(ctx context.Context, message *entity.MessagesEntity)) *MessagesRepositoryMock_RecordDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.MessagesEntity))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_RecordDelivery_Call) Return(_a0 bool, _a1 error) *MessagesRepositoryMock_RecordDelivery_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_RecordDelivery_Call) RunAndReturn(run func(context.Context, *entity.MessagesEntity) (bool, error)) *MessagesRepositoryMock_RecordDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseExpiredLeases provides a mock function with given fields: ctx
+func (_m *MessagesRepositoryMock) ReleaseExpiredLeases(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseExpiredLeases")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_ReleaseExpiredLeases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseExpiredLeases'
+type MessagesRepositoryMock_ReleaseExpiredLeases_Call struct {
+	*mock.Call
+}
+
+// ReleaseExpiredLeases is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MessagesRepositoryMock_Expecter) ReleaseExpiredLeases(ctx interface{}) *MessagesRepositoryMock_ReleaseExpiredLeases_Call {
+	return &MessagesRepositoryMock_ReleaseExpiredLeases_Call{Call: _e.mock.On("ReleaseExpiredLeases", ctx)}
+}
+
+func (_c *MessagesRepositoryMock_ReleaseExpiredLeases_Call) Run(run func(ctx context.Context)) *MessagesRepositoryMock_ReleaseExpiredLeases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_ReleaseExpiredLeases_Call) Return(_a0 int64, _a1 error) *MessagesRepositoryMock_ReleaseExpiredLeases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_ReleaseExpiredLeases_Call) RunAndReturn(run func(context.Context) (int64, error)) *MessagesRepositoryMock_ReleaseExpiredLeases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveBatch provides a mock function with given fields: ctx, messages
+func (_m *MessagesRepositoryMock) SaveBatch(ctx context.Context, messages []*entity.MessagesEntity) error {
+	ret := _m.Called(ctx, messages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.MessagesEntity) error); ok {
+		r0 = rf(ctx, messages)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -158,31 +557,205 @@ func (_m *MessagesRepositoryMock) Save(ctx context.Context, message *entity.Mess
 	return r0
 }
 
-// MessagesRepositoryMock_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
-type MessagesRepositoryMock_Save_Call struct {
+// MessagesRepositoryMock_SaveBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveBatch'
+type MessagesRepositoryMock_SaveBatch_Call struct {
+	*mock.Call
+}
+
+// SaveBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messages []*entity.MessagesEntity
+func (_e *MessagesRepositoryMock_Expecter) SaveBatch(ctx interface{}, messages interface{}) *MessagesRepositoryMock_SaveBatch_Call {
+	return &MessagesRepositoryMock_SaveBatch_Call{Call: _e.mock.On("SaveBatch", ctx, messages)}
+}
+
+func (_c *MessagesRepositoryMock_SaveBatch_Call) Run(run func(ctx context.Context, messages []*entity.MessagesEntity)) *MessagesRepositoryMock_SaveBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*entity.MessagesEntity))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_SaveBatch_Call) Return(_a0 error) *MessagesRepositoryMock_SaveBatch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_SaveBatch_Call) RunAndReturn(run func(context.Context, []*entity.MessagesEntity) error) *MessagesRepositoryMock_SaveBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveClaimed provides a mock function with given fields: ctx, message, owner
+func (_m *MessagesRepositoryMock) SaveClaimed(ctx context.Context, message *entity.MessagesEntity, owner string) (bool, error) {
+	ret := _m.Called(ctx, message, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveClaimed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity, string) (bool, error)); ok {
+		return rf(ctx, message, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity, string) bool); ok {
+		r0 = rf(ctx, message, owner)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.MessagesEntity, string) error); ok {
+		r1 = rf(ctx, message, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_SaveClaimed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveClaimed'
+type MessagesRepositoryMock_SaveClaimed_Call struct {
+	*mock.Call
+}
+
+// SaveClaimed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *entity.MessagesEntity
+//   - owner string
+func (_e *MessagesRepositoryMock_Expecter) SaveClaimed(ctx interface{}, message interface{}, owner interface{}) *MessagesRepositoryMock_SaveClaimed_Call {
+	return &MessagesRepositoryMock_SaveClaimed_Call{Call: _e.mock.On("SaveClaimed", ctx, message, owner)}
+}
+
+func (_c *MessagesRepositoryMock_SaveClaimed_Call) Run(run func(ctx context.Context, message *entity.MessagesEntity, owner string)) *MessagesRepositoryMock_SaveClaimed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.MessagesEntity), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_SaveClaimed_Call) Return(_a0 bool, _a1 error) *MessagesRepositoryMock_SaveClaimed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_SaveClaimed_Call) RunAndReturn(run func(context.Context, *entity.MessagesEntity, string) (bool, error)) *MessagesRepositoryMock_SaveClaimed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchMessages provides a mock function with given fields: ctx, filter
+func (_m *MessagesRepositoryMock) SearchMessages(ctx context.Context, filter repository.MessageFilter) ([]*entity.MessagesEntity, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchMessages")
+	}
+
+	var r0 []*entity.MessagesEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.MessageFilter) ([]*entity.MessagesEntity, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.MessageFilter) []*entity.MessagesEntity); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.MessagesEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.MessageFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_SearchMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchMessages'
+type MessagesRepositoryMock_SearchMessages_Call struct {
 	*mock.Call
 }
 
-// Save is a helper method to define mock.On call
+// SearchMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.MessageFilter
+func (_e *MessagesRepositoryMock_Expecter) SearchMessages(ctx interface{}, filter interface{}) *MessagesRepositoryMock_SearchMessages_Call {
+	return &MessagesRepositoryMock_SearchMessages_Call{Call: _e.mock.On("SearchMessages", ctx, filter)}
+}
+
+func (_c *MessagesRepositoryMock_SearchMessages_Call) Run(run func(ctx context.Context, filter repository.MessageFilter)) *MessagesRepositoryMock_SearchMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.MessageFilter))
+	})
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_SearchMessages_Call) Return(_a0 []*entity.MessagesEntity, _a1 error) *MessagesRepositoryMock_SearchMessages_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MessagesRepositoryMock_SearchMessages_Call) RunAndReturn(run func(context.Context, repository.MessageFilter) ([]*entity.MessagesEntity, error)) *MessagesRepositoryMock_SearchMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePending provides a mock function with given fields: ctx, message
+func (_m *MessagesRepositoryMock) UpdatePending(ctx context.Context, message *entity.MessagesEntity) (bool, error) {
+	ret := _m.Called(ctx, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePending")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity) (bool, error)); ok {
+		return rf(ctx, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity) bool); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.MessagesEntity) error); ok {
+		r1 = rf(ctx, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MessagesRepositoryMock_UpdatePending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePending'
+type MessagesRepositoryMock_UpdatePending_Call struct {
+	*mock.Call
+}
+
+// UpdatePending is a helper method to define mock.On call
 //   - ctx context.Context
 //   - message *entity.MessagesEntity
-func (_e *MessagesRepositoryMock_Expecter) Save(ctx interface{}, message interface{}) *MessagesRepositoryMock_Save_Call {
-	return &MessagesRepositoryMock_Save_Call{Call: _e.mock.On("Save", ctx, message)}
+func (_e *MessagesRepositoryMock_Expecter) UpdatePending(ctx interface{}, message interface{}) *MessagesRepositoryMock_UpdatePending_Call {
+	return &MessagesRepositoryMock_UpdatePending_Call{Call: _e.mock.On("UpdatePending", ctx, message)}
 }
 
-func (_c *MessagesRepositoryMock_Save_Call) Run(run func(ctx context.Context, message *entity.MessagesEntity)) *MessagesRepositoryMock_Save_Call {
+func (_c *MessagesRepositoryMock_UpdatePending_Call) Run(run func(ctx context.Context, message *entity.MessagesEntity)) *MessagesRepositoryMock_UpdatePending_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(*entity.MessagesEntity))
 	})
 	return _c
 }
 
-func (_c *MessagesRepositoryMock_Save_Call) Return(_a0 error) *MessagesRepositoryMock_Save_Call {
-	_c.Call.Return(_a0)
+func (_c *MessagesRepositoryMock_UpdatePending_Call) Return(_a0 bool, _a1 error) *MessagesRepositoryMock_UpdatePending_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MessagesRepositoryMock_Save_Call) RunAndReturn(run func(context.Context, *entity.MessagesEntity) error) *MessagesRepositoryMock_Save_Call {
+func (_c *MessagesRepositoryMock_UpdatePending_Call) RunAndReturn(run func(context.Context, *entity.MessagesEntity) (bool, error)) *MessagesRepositoryMock_UpdatePending_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,253 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "message-scheduler/internal/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepositoryMock is an autogenerated mock type for the OutboxRepository type
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+type OutboxRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OutboxRepositoryMock) EXPECT() *OutboxRepositoryMock_Expecter {
+	return &OutboxRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// ClaimEvents provides a mock function with given fields: ctx, owner, lease, recordLimit
+func (_m *OutboxRepositoryMock) ClaimEvents(ctx context.Context, owner string, lease time.Duration, recordLimit int) ([]*entity.OutboxEventEntity, error) {
+	ret := _m.Called(ctx, owner, lease, recordLimit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimEvents")
+	}
+
+	var r0 []*entity.OutboxEventEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, int) ([]*entity.OutboxEventEntity, error)); ok {
+		return rf(ctx, owner, lease, recordLimit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration, int) []*entity.OutboxEventEntity); ok {
+		r0 = rf(ctx, owner, lease, recordLimit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.OutboxEventEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration, int) error); ok {
+		r1 = rf(ctx, owner, lease, recordLimit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxRepositoryMock_ClaimEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimEvents'
+type OutboxRepositoryMock_ClaimEvents_Call struct {
+	*mock.Call
+}
+
+// ClaimEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - owner string
+//   - lease time.Duration
+//   - recordLimit int
+func (_e *OutboxRepositoryMock_Expecter) ClaimEvents(ctx interface{}, owner interface{}, lease interface{}, recordLimit interface{}) *OutboxRepositoryMock_ClaimEvents_Call {
+	return &OutboxRepositoryMock_ClaimEvents_Call{Call: _e.mock.On("ClaimEvents", ctx, owner, lease, recordLimit)}
+}
+
+func (_c *OutboxRepositoryMock_ClaimEvents_Call) Run(run func(ctx context.Context, owner string, lease time.Duration, recordLimit int)) *OutboxRepositoryMock_ClaimEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_ClaimEvents_Call) Return(_a0 []*entity.OutboxEventEntity, _a1 error) *OutboxRepositoryMock_ClaimEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_ClaimEvents_Call) RunAndReturn(run func(context.Context, string, time.Duration, int) ([]*entity.OutboxEventEntity, error)) *OutboxRepositoryMock_ClaimEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePublished provides a mock function with given fields: ctx, olderThan
+func (_m *OutboxRepositoryMock) DeletePublished(ctx context.Context, olderThan time.Duration) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePublished")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (int64, error)); ok {
+		return rf(ctx, olderThan)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxRepositoryMock_DeletePublished_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePublished'
+type OutboxRepositoryMock_DeletePublished_Call struct {
+	*mock.Call
+}
+
+// DeletePublished is a helper method to define mock.On call
+//   - ctx context.Context
+//   - olderThan time.Duration
+func (_e *OutboxRepositoryMock_Expecter) DeletePublished(ctx interface{}, olderThan interface{}) *OutboxRepositoryMock_DeletePublished_Call {
+	return &OutboxRepositoryMock_DeletePublished_Call{Call: _e.mock.On("DeletePublished", ctx, olderThan)}
+}
+
+func (_c *OutboxRepositoryMock_DeletePublished_Call) Run(run func(ctx context.Context, olderThan time.Duration)) *OutboxRepositoryMock_DeletePublished_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_DeletePublished_Call) Return(_a0 int64, _a1 error) *OutboxRepositoryMock_DeletePublished_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_DeletePublished_Call) RunAndReturn(run func(context.Context, time.Duration) (int64, error)) *OutboxRepositoryMock_DeletePublished_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkPublished provides a mock function with given fields: ctx, ids
+func (_m *OutboxRepositoryMock) MarkPublished(ctx context.Context, ids []string) error {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPublished")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OutboxRepositoryMock_MarkPublished_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkPublished'
+type OutboxRepositoryMock_MarkPublished_Call struct {
+	*mock.Call
+}
+
+// MarkPublished is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []string
+func (_e *OutboxRepositoryMock_Expecter) MarkPublished(ctx interface{}, ids interface{}) *OutboxRepositoryMock_MarkPublished_Call {
+	return &OutboxRepositoryMock_MarkPublished_Call{Call: _e.mock.On("MarkPublished", ctx, ids)}
+}
+
+func (_c *OutboxRepositoryMock_MarkPublished_Call) Run(run func(ctx context.Context, ids []string)) *OutboxRepositoryMock_MarkPublished_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_MarkPublished_Call) Return(_a0 error) *OutboxRepositoryMock_MarkPublished_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_MarkPublished_Call) RunAndReturn(run func(context.Context, []string) error) *OutboxRepositoryMock_MarkPublished_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseEvents provides a mock function with given fields: ctx, ids, retryAfter, publishErr
+func (_m *OutboxRepositoryMock) ReleaseEvents(ctx context.Context, ids []string, retryAfter time.Duration, publishErr string) error {
+	ret := _m.Called(ctx, ids, retryAfter, publishErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Duration, string) error); ok {
+		r0 = rf(ctx, ids, retryAfter, publishErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OutboxRepositoryMock_ReleaseEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseEvents'
+type OutboxRepositoryMock_ReleaseEvents_Call struct {
+	*mock.Call
+}
+
+// ReleaseEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []string
+//   - retryAfter time.Duration
+//   - publishErr string
+func (_e *OutboxRepositoryMock_Expecter) ReleaseEvents(ctx interface{}, ids interface{}, retryAfter interface{}, publishErr interface{}) *OutboxRepositoryMock_ReleaseEvents_Call {
+	return &OutboxRepositoryMock_ReleaseEvents_Call{Call: _e.mock.On("ReleaseEvents", ctx, ids, retryAfter, publishErr)}
+}
+
+func (_c *OutboxRepositoryMock_ReleaseEvents_Call) Run(run func(ctx context.Context, ids []string, retryAfter time.Duration, publishErr string)) *OutboxRepositoryMock_ReleaseEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(time.Duration), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_ReleaseEvents_Call) Return(_a0 error) *OutboxRepositoryMock_ReleaseEvents_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *OutboxRepositoryMock_ReleaseEvents_Call) RunAndReturn(run func(context.Context, []string, time.Duration, string) error) *OutboxRepositoryMock_ReleaseEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOutboxRepositoryMock creates a new instance of OutboxRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRepositoryMock {
+	mock := &OutboxRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
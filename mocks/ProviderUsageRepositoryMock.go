@@ -0,0 +1,203 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ProviderUsageRepositoryMock is an autogenerated mock type for the ProviderUsageRepository type
+type ProviderUsageRepositoryMock struct {
+	mock.Mock
+}
+
+type ProviderUsageRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ProviderUsageRepositoryMock) EXPECT() *ProviderUsageRepositoryMock_Expecter {
+	return &ProviderUsageRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// Count provides a mock function with given fields: ctx, provider, day
+func (_m *ProviderUsageRepositoryMock) Count(ctx context.Context, provider string, day time.Time) (int, error) {
+	ret := _m.Called(ctx, provider, day)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int, error)); ok {
+		return rf(ctx, provider, day)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int); ok {
+		r0 = rf(ctx, provider, day)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, provider, day)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProviderUsageRepositoryMock_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type ProviderUsageRepositoryMock_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - day time.Time
+func (_e *ProviderUsageRepositoryMock_Expecter) Count(ctx interface{}, provider interface{}, day interface{}) *ProviderUsageRepositoryMock_Count_Call {
+	return &ProviderUsageRepositoryMock_Count_Call{Call: _e.mock.On("Count", ctx, provider, day)}
+}
+
+func (_c *ProviderUsageRepositoryMock_Count_Call) Run(run func(ctx context.Context, provider string, day time.Time)) *ProviderUsageRepositoryMock_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ProviderUsageRepositoryMock_Count_Call) Return(_a0 int, _a1 error) *ProviderUsageRepositoryMock_Count_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ProviderUsageRepositoryMock_Count_Call) RunAndReturn(run func(context.Context, string, time.Time) (int, error)) *ProviderUsageRepositoryMock_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function with given fields: ctx, provider, day
+func (_m *ProviderUsageRepositoryMock) Release(ctx context.Context, provider string, day time.Time) error {
+	ret := _m.Called(ctx, provider, day)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, provider, day)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ProviderUsageRepositoryMock_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type ProviderUsageRepositoryMock_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - day time.Time
+func (_e *ProviderUsageRepositoryMock_Expecter) Release(ctx interface{}, provider interface{}, day interface{}) *ProviderUsageRepositoryMock_Release_Call {
+	return &ProviderUsageRepositoryMock_Release_Call{Call: _e.mock.On("Release", ctx, provider, day)}
+}
+
+func (_c *ProviderUsageRepositoryMock_Release_Call) Run(run func(ctx context.Context, provider string, day time.Time)) *ProviderUsageRepositoryMock_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *ProviderUsageRepositoryMock_Release_Call) Return(_a0 error) *ProviderUsageRepositoryMock_Release_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ProviderUsageRepositoryMock_Release_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *ProviderUsageRepositoryMock_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reserve provides a mock function with given fields: ctx, provider, day, limit
+func (_m *ProviderUsageRepositoryMock) Reserve(ctx context.Context, provider string, day time.Time, limit int) (bool, error) {
+	ret := _m.Called(ctx, provider, day, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, int) (bool, error)); ok {
+		return rf(ctx, provider, day, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, int) bool); ok {
+		r0 = rf(ctx, provider, day, limit)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, int) error); ok {
+		r1 = rf(ctx, provider, day, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProviderUsageRepositoryMock_Reserve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reserve'
+type ProviderUsageRepositoryMock_Reserve_Call struct {
+	*mock.Call
+}
+
+// Reserve is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - day time.Time
+//   - limit int
+func (_e *ProviderUsageRepositoryMock_Expecter) Reserve(ctx interface{}, provider interface{}, day interface{}, limit interface{}) *ProviderUsageRepositoryMock_Reserve_Call {
+	return &ProviderUsageRepositoryMock_Reserve_Call{Call: _e.mock.On("Reserve", ctx, provider, day, limit)}
+}
+
+func (_c *ProviderUsageRepositoryMock_Reserve_Call) Run(run func(ctx context.Context, provider string, day time.Time, limit int)) *ProviderUsageRepositoryMock_Reserve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *ProviderUsageRepositoryMock_Reserve_Call) Return(_a0 bool, _a1 error) *ProviderUsageRepositoryMock_Reserve_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ProviderUsageRepositoryMock_Reserve_Call) RunAndReturn(run func(context.Context, string, time.Time, int) (bool, error)) *ProviderUsageRepositoryMock_Reserve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewProviderUsageRepositoryMock creates a new instance of ProviderUsageRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProviderUsageRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProviderUsageRepositoryMock {
+	mock := &ProviderUsageRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,142 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "message-scheduler/internal/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SchedulerSettingsRepositoryMock is an autogenerated mock type for the SchedulerSettingsRepository type
+type SchedulerSettingsRepositoryMock struct {
+	mock.Mock
+}
+
+type SchedulerSettingsRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SchedulerSettingsRepositoryMock) EXPECT() *SchedulerSettingsRepositoryMock_Expecter {
+	return &SchedulerSettingsRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx
+func (_m *SchedulerSettingsRepositoryMock) Get(ctx context.Context) (*entity.SchedulerSettingsEntity, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *entity.SchedulerSettingsEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entity.SchedulerSettingsEntity, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.SchedulerSettingsEntity); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.SchedulerSettingsEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SchedulerSettingsRepositoryMock_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type SchedulerSettingsRepositoryMock_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *SchedulerSettingsRepositoryMock_Expecter) Get(ctx interface{}) *SchedulerSettingsRepositoryMock_Get_Call {
+	return &SchedulerSettingsRepositoryMock_Get_Call{Call: _e.mock.On("Get", ctx)}
+}
+
+func (_c *SchedulerSettingsRepositoryMock_Get_Call) Run(run func(ctx context.Context)) *SchedulerSettingsRepositoryMock_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *SchedulerSettingsRepositoryMock_Get_Call) Return(_a0 *entity.SchedulerSettingsEntity, _a1 error) *SchedulerSettingsRepositoryMock_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SchedulerSettingsRepositoryMock_Get_Call) RunAndReturn(run func(context.Context) (*entity.SchedulerSettingsEntity, error)) *SchedulerSettingsRepositoryMock_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, settings
+func (_m *SchedulerSettingsRepositoryMock) Save(ctx context.Context, settings *entity.SchedulerSettingsEntity) error {
+	ret := _m.Called(ctx, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SchedulerSettingsEntity) error); ok {
+		r0 = rf(ctx, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SchedulerSettingsRepositoryMock_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type SchedulerSettingsRepositoryMock_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - settings *entity.SchedulerSettingsEntity
+func (_e *SchedulerSettingsRepositoryMock_Expecter) Save(ctx interface{}, settings interface{}) *SchedulerSettingsRepositoryMock_Save_Call {
+	return &SchedulerSettingsRepositoryMock_Save_Call{Call: _e.mock.On("Save", ctx, settings)}
+}
+
+func (_c *SchedulerSettingsRepositoryMock_Save_Call) Run(run func(ctx context.Context, settings *entity.SchedulerSettingsEntity)) *SchedulerSettingsRepositoryMock_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.SchedulerSettingsEntity))
+	})
+	return _c
+}
+
+func (_c *SchedulerSettingsRepositoryMock_Save_Call) Return(_a0 error) *SchedulerSettingsRepositoryMock_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SchedulerSettingsRepositoryMock_Save_Call) RunAndReturn(run func(context.Context, *entity.SchedulerSettingsEntity) error) *SchedulerSettingsRepositoryMock_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSchedulerSettingsRepositoryMock creates a new instance of SchedulerSettingsRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSchedulerSettingsRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SchedulerSettingsRepositoryMock {
+	mock := &SchedulerSettingsRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "message-scheduler/internal/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SendAttemptsRepositoryMock is an autogenerated mock type for the SendAttemptsRepository type
+type SendAttemptsRepositoryMock struct {
+	mock.Mock
+}
+
+type SendAttemptsRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SendAttemptsRepositoryMock) EXPECT() *SendAttemptsRepositoryMock_Expecter {
+	return &SendAttemptsRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// Begin provides a mock function with given fields: ctx, sendAttempt
+func (_m *SendAttemptsRepositoryMock) Begin(ctx context.Context, sendAttempt *entity.SendAttemptEntity) (*entity.SendAttemptEntity, error) {
+	ret := _m.Called(ctx, sendAttempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *entity.SendAttemptEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SendAttemptEntity) (*entity.SendAttemptEntity, error)); ok {
+		return rf(ctx, sendAttempt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SendAttemptEntity) *entity.SendAttemptEntity); ok {
+		r0 = rf(ctx, sendAttempt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.SendAttemptEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.SendAttemptEntity) error); ok {
+		r1 = rf(ctx, sendAttempt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendAttemptsRepositoryMock_Begin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Begin'
+type SendAttemptsRepositoryMock_Begin_Call struct {
+	*mock.Call
+}
+
+// Begin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sendAttempt *entity.SendAttemptEntity
+func (_e *SendAttemptsRepositoryMock_Expecter) Begin(ctx interface{}, sendAttempt interface{}) *SendAttemptsRepositoryMock_Begin_Call {
+	return &SendAttemptsRepositoryMock_Begin_Call{Call: _e.mock.On("Begin", ctx, sendAttempt)}
+}
+
+func (_c *SendAttemptsRepositoryMock_Begin_Call) Run(run func(ctx context.Context, sendAttempt *entity.SendAttemptEntity)) *SendAttemptsRepositoryMock_Begin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.SendAttemptEntity))
+	})
+	return _c
+}
+
+func (_c *SendAttemptsRepositoryMock_Begin_Call) Return(_a0 *entity.SendAttemptEntity, _a1 error) *SendAttemptsRepositoryMock_Begin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SendAttemptsRepositoryMock_Begin_Call) RunAndReturn(run func(context.Context, *entity.SendAttemptEntity) (*entity.SendAttemptEntity, error)) *SendAttemptsRepositoryMock_Begin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Finish provides a mock function with given fields: ctx, sendAttempt
+func (_m *SendAttemptsRepositoryMock) Finish(ctx context.Context, sendAttempt *entity.SendAttemptEntity) error {
+	ret := _m.Called(ctx, sendAttempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Finish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.SendAttemptEntity) error); ok {
+		r0 = rf(ctx, sendAttempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendAttemptsRepositoryMock_Finish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Finish'
+type SendAttemptsRepositoryMock_Finish_Call struct {
+	*mock.Call
+}
+
+// Finish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sendAttempt *entity.SendAttemptEntity
+func (_e *SendAttemptsRepositoryMock_Expecter) Finish(ctx interface{}, sendAttempt interface{}) *SendAttemptsRepositoryMock_Finish_Call {
+	return &SendAttemptsRepositoryMock_Finish_Call{Call: _e.mock.On("Finish", ctx, sendAttempt)}
+}
+
+func (_c *SendAttemptsRepositoryMock_Finish_Call) Run(run func(ctx context.Context, sendAttempt *entity.SendAttemptEntity)) *SendAttemptsRepositoryMock_Finish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.SendAttemptEntity))
+	})
+	return _c
+}
+
+func (_c *SendAttemptsRepositoryMock_Finish_Call) Return(_a0 error) *SendAttemptsRepositoryMock_Finish_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SendAttemptsRepositoryMock_Finish_Call) RunAndReturn(run func(context.Context, *entity.SendAttemptEntity) error) *SendAttemptsRepositoryMock_Finish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSendAttemptsRepositoryMock creates a new instance of SendAttemptsRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSendAttemptsRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SendAttemptsRepositoryMock {
+	mock := &SendAttemptsRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
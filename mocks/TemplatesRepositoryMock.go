@@ -0,0 +1,414 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "message-scheduler/internal/domain/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TemplatesRepositoryMock is an autogenerated mock type for the TemplatesRepository type
+type TemplatesRepositoryMock struct {
+	mock.Mock
+}
+
+type TemplatesRepositoryMock_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TemplatesRepositoryMock) EXPECT() *TemplatesRepositoryMock_Expecter {
+	return &TemplatesRepositoryMock_Expecter{mock: &_m.Mock}
+}
+
+// AddVersion provides a mock function with given fields: ctx, template
+func (_m *TemplatesRepositoryMock) AddVersion(ctx context.Context, template *entity.TemplateEntity) error {
+	ret := _m.Called(ctx, template)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.TemplateEntity) error); ok {
+		r0 = rf(ctx, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TemplatesRepositoryMock_AddVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddVersion'
+type TemplatesRepositoryMock_AddVersion_Call struct {
+	*mock.Call
+}
+
+// AddVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - template *entity.TemplateEntity
+func (_e *TemplatesRepositoryMock_Expecter) AddVersion(ctx interface{}, template interface{}) *TemplatesRepositoryMock_AddVersion_Call {
+	return &TemplatesRepositoryMock_AddVersion_Call{Call: _e.mock.On("AddVersion", ctx, template)}
+}
+
+func (_c *TemplatesRepositoryMock_AddVersion_Call) Run(run func(ctx context.Context, template *entity.TemplateEntity)) *TemplatesRepositoryMock_AddVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.TemplateEntity))
+	})
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_AddVersion_Call) Return(_a0 error) *TemplatesRepositoryMock_AddVersion_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_AddVersion_Call) RunAndReturn(run func(context.Context, *entity.TemplateEntity) error) *TemplatesRepositoryMock_AddVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, template
+func (_m *TemplatesRepositoryMock) Create(ctx context.Context, template *entity.TemplateEntity) error {
+	ret := _m.Called(ctx, template)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.TemplateEntity) error); ok {
+		r0 = rf(ctx, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TemplatesRepositoryMock_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type TemplatesRepositoryMock_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - template *entity.TemplateEntity
+func (_e *TemplatesRepositoryMock_Expecter) Create(ctx interface{}, template interface{}) *TemplatesRepositoryMock_Create_Call {
+	return &TemplatesRepositoryMock_Create_Call{Call: _e.mock.On("Create", ctx, template)}
+}
+
+func (_c *TemplatesRepositoryMock_Create_Call) Run(run func(ctx context.Context, template *entity.TemplateEntity)) *TemplatesRepositoryMock_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.TemplateEntity))
+	})
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_Create_Call) Return(_a0 error) *TemplatesRepositoryMock_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_Create_Call) RunAndReturn(run func(context.Context, *entity.TemplateEntity) error) *TemplatesRepositoryMock_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *TemplatesRepositoryMock) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TemplatesRepositoryMock_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type TemplatesRepositoryMock_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *TemplatesRepositoryMock_Expecter) Delete(ctx interface{}, id interface{}) *TemplatesRepositoryMock_Delete_Call {
+	return &TemplatesRepositoryMock_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *TemplatesRepositoryMock_Delete_Call) Run(run func(ctx context.Context, id string)) *TemplatesRepositoryMock_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_Delete_Call) Return(_a0 error) *TemplatesRepositoryMock_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_Delete_Call) RunAndReturn(run func(context.Context, string) error) *TemplatesRepositoryMock_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTemplate provides a mock function with given fields: ctx, id, version
+func (_m *TemplatesRepositoryMock) GetTemplate(ctx context.Context, id string, version int) (*entity.TemplateEntity, error) {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTemplate")
+	}
+
+	var r0 *entity.TemplateEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*entity.TemplateEntity, error)); ok {
+		return rf(ctx, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *entity.TemplateEntity); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TemplateEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesRepositoryMock_GetTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTemplate'
+type TemplatesRepositoryMock_GetTemplate_Call struct {
+	*mock.Call
+}
+
+// GetTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - version int
+func (_e *TemplatesRepositoryMock_Expecter) GetTemplate(ctx interface{}, id interface{}, version interface{}) *TemplatesRepositoryMock_GetTemplate_Call {
+	return &TemplatesRepositoryMock_GetTemplate_Call{Call: _e.mock.On("GetTemplate", ctx, id, version)}
+}
+
+func (_c *TemplatesRepositoryMock_GetTemplate_Call) Run(run func(ctx context.Context, id string, version int)) *TemplatesRepositoryMock_GetTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_GetTemplate_Call) Return(_a0 *entity.TemplateEntity, _a1 error) *TemplatesRepositoryMock_GetTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_GetTemplate_Call) RunAndReturn(run func(context.Context, string, int) (*entity.TemplateEntity, error)) *TemplatesRepositoryMock_GetTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPendingRenderings provides a mock function with given fields: ctx, id
+func (_m *TemplatesRepositoryMock) ListPendingRenderings(ctx context.Context, id string) ([]*entity.MessagesEntity, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingRenderings")
+	}
+
+	var r0 []*entity.MessagesEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.MessagesEntity, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.MessagesEntity); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.MessagesEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesRepositoryMock_ListPendingRenderings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPendingRenderings'
+type TemplatesRepositoryMock_ListPendingRenderings_Call struct {
+	*mock.Call
+}
+
+// ListPendingRenderings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *TemplatesRepositoryMock_Expecter) ListPendingRenderings(ctx interface{}, id interface{}) *TemplatesRepositoryMock_ListPendingRenderings_Call {
+	return &TemplatesRepositoryMock_ListPendingRenderings_Call{Call: _e.mock.On("ListPendingRenderings", ctx, id)}
+}
+
+func (_c *TemplatesRepositoryMock_ListPendingRenderings_Call) Run(run func(ctx context.Context, id string)) *TemplatesRepositoryMock_ListPendingRenderings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_ListPendingRenderings_Call) Return(_a0 []*entity.MessagesEntity, _a1 error) *TemplatesRepositoryMock_ListPendingRenderings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_ListPendingRenderings_Call) RunAndReturn(run func(context.Context, string) ([]*entity.MessagesEntity, error)) *TemplatesRepositoryMock_ListPendingRenderings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTemplates provides a mock function with given fields: ctx
+func (_m *TemplatesRepositoryMock) ListTemplates(ctx context.Context) ([]*entity.TemplateEntity, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTemplates")
+	}
+
+	var r0 []*entity.TemplateEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entity.TemplateEntity, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entity.TemplateEntity); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.TemplateEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesRepositoryMock_ListTemplates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTemplates'
+type TemplatesRepositoryMock_ListTemplates_Call struct {
+	*mock.Call
+}
+
+// ListTemplates is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *TemplatesRepositoryMock_Expecter) ListTemplates(ctx interface{}) *TemplatesRepositoryMock_ListTemplates_Call {
+	return &TemplatesRepositoryMock_ListTemplates_Call{Call: _e.mock.On("ListTemplates", ctx)}
+}
+
+func (_c *TemplatesRepositoryMock_ListTemplates_Call) Run(run func(ctx context.Context)) *TemplatesRepositoryMock_ListTemplates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_ListTemplates_Call) Return(_a0 []*entity.TemplateEntity, _a1 error) *TemplatesRepositoryMock_ListTemplates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_ListTemplates_Call) RunAndReturn(run func(context.Context) ([]*entity.TemplateEntity, error)) *TemplatesRepositoryMock_ListTemplates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListVersions provides a mock function with given fields: ctx, id
+func (_m *TemplatesRepositoryMock) ListVersions(ctx context.Context, id string) ([]*entity.TemplateEntity, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListVersions")
+	}
+
+	var r0 []*entity.TemplateEntity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entity.TemplateEntity, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entity.TemplateEntity); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.TemplateEntity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesRepositoryMock_ListVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListVersions'
+type TemplatesRepositoryMock_ListVersions_Call struct {
+	*mock.Call
+}
+
+// ListVersions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *TemplatesRepositoryMock_Expecter) ListVersions(ctx interface{}, id interface{}) *TemplatesRepositoryMock_ListVersions_Call {
+	return &TemplatesRepositoryMock_ListVersions_Call{Call: _e.mock.On("ListVersions", ctx, id)}
+}
+
+func (_c *TemplatesRepositoryMock_ListVersions_Call) Run(run func(ctx context.Context, id string)) *TemplatesRepositoryMock_ListVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_ListVersions_Call) Return(_a0 []*entity.TemplateEntity, _a1 error) *TemplatesRepositoryMock_ListVersions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesRepositoryMock_ListVersions_Call) RunAndReturn(run func(context.Context, string) ([]*entity.TemplateEntity, error)) *TemplatesRepositoryMock_ListVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTemplatesRepositoryMock creates a new instance of TemplatesRepositoryMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTemplatesRepositoryMock(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TemplatesRepositoryMock {
+	mock := &TemplatesRepositoryMock{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,9 +4,11 @@ package mocks
 
 import (
 	context "context"
-	webhook "message-scheduler/internal/infra/client/webhook"
+	entity "message-scheduler/internal/domain/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	webhook "message-scheduler/internal/infra/client/webhook"
 )
 
 // WebhookClientMock is an autogenerated mock type for the WebhookClient type
@@ -22,9 +24,9 @@ func (_m *WebhookClientMock) EXPECT() *WebhookClientMock_Expecter {
 	return &WebhookClientMock_Expecter{mock: &_m.Mock}
 }
 
-// SendMessage provides a mock function with given fields: ctx, to, content
-func (_m *WebhookClientMock) SendMessage(ctx context.Context, to string, content string) (*webhook.WebhookResponse, error) {
-	ret := _m.Called(ctx, to, content)
+// SendMessage provides a mock function with given fields: ctx, message, idempotencyKey
+func (_m *WebhookClientMock) SendMessage(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string) (*webhook.WebhookResponse, error) {
+	ret := _m.Called(ctx, message, idempotencyKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendMessage")
@@ -32,19 +34,19 @@ func (_m *WebhookClientMock) SendMessage(ctx context.Context, to string, content
 
 	var r0 *webhook.WebhookResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*webhook.WebhookResponse, error)); ok {
-		return rf(ctx, to, content)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity, string) (*webhook.WebhookResponse, error)); ok {
+		return rf(ctx, message, idempotencyKey)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *webhook.WebhookResponse); ok {
-		r0 = rf(ctx, to, content)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.MessagesEntity, string) *webhook.WebhookResponse); ok {
+		r0 = rf(ctx, message, idempotencyKey)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*webhook.WebhookResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, to, content)
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.MessagesEntity, string) error); ok {
+		r1 = rf(ctx, message, idempotencyKey)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -52,22 +54,22 @@ func (_m *WebhookClientMock) SendMessage(ctx context.Context, to string, content
 	return r0, r1
 }
 
-// WebhookClientMock_SendMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartMessageScheduler'
+// WebhookClientMock_SendMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendMessage'
 type WebhookClientMock_SendMessage_Call struct {
 	*mock.Call
 }
 
 // SendMessage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - to string
-//   - content string
-func (_e *WebhookClientMock_Expecter) SendMessage(ctx interface{}, to interface{}, content interface{}) *WebhookClientMock_SendMessage_Call {
-	return &WebhookClientMock_SendMessage_Call{Call: _e.mock.On("SendMessage", ctx, to, content)}
+//   - message *entity.MessagesEntity
+//   - idempotencyKey string
+func (_e *WebhookClientMock_Expecter) SendMessage(ctx interface{}, message interface{}, idempotencyKey interface{}) *WebhookClientMock_SendMessage_Call {
+	return &WebhookClientMock_SendMessage_Call{Call: _e.mock.On("SendMessage", ctx, message, idempotencyKey)}
 }
 
-func (_c *WebhookClientMock_SendMessage_Call) Run(run func(ctx context.Context, to string, content string)) *WebhookClientMock_SendMessage_Call {
+func (_c *WebhookClientMock_SendMessage_Call) Run(run func(ctx context.Context, message *entity.MessagesEntity, idempotencyKey string)) *WebhookClientMock_SendMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(*entity.MessagesEntity), args[2].(string))
 	})
 	return _c
 }
@@ -77,7 +79,7 @@ func (_c *WebhookClientMock_SendMessage_Call) Return(_a0 *webhook.WebhookRespons
 	return _c
 }
 
-func (_c *WebhookClientMock_SendMessage_Call) RunAndReturn(run func(context.Context, string, string) (*webhook.WebhookResponse, error)) *WebhookClientMock_SendMessage_Call {
+func (_c *WebhookClientMock_SendMessage_Call) RunAndReturn(run func(context.Context, *entity.MessagesEntity, string) (*webhook.WebhookResponse, error)) *WebhookClientMock_SendMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
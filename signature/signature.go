@@ -0,0 +1,198 @@
+// Package signature signs webhook requests with HMAC-SHA256 and verifies them on the receiving side.
+//
+// A signed request carries a header in the form
+//
+//	X-Webhook-Signature: t=1735797600,v1=5257a869...,v1=0d6b2a81...
+//
+// where t is the Unix time of signing and every v1 is the hex encoded HMAC-SHA256 of "<t>.<body>"
+// under one of the sender's active secrets. Sending one v1 per secret lets secrets be rotated
+// without downtime: the sender signs with the old and the new secret until every receiver has
+// switched to the new one.
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header is the request header carrying the signature.
+const Header = "X-Webhook-Signature"
+
+// DefaultTolerance is how far the signing time may be from the receiver's clock before a request is
+// rejected as a possible replay.
+const DefaultTolerance = 5 * time.Minute
+
+const (
+	timestampKey = "t"
+	signatureKey = "v1"
+)
+
+var (
+	ErrNoSecrets         = errors.New("signature: at least one secret is required")
+	ErrMissingHeader     = errors.New("signature: header is missing")
+	ErrInvalidHeader     = errors.New("signature: header is malformed")
+	ErrTimestampExpired  = errors.New("signature: timestamp is outside the tolerance")
+	ErrSignatureMismatch = errors.New("signature: no signature matches")
+)
+
+// Signer produces signature headers with every active secret.
+type Signer struct {
+	secrets [][]byte
+	now     func() time.Time
+}
+
+// NewSigner returns a Signer for the given secrets; list the newest secret first while rotating.
+func NewSigner(secrets ...string) (*Signer, error) {
+	keys, err := toKeys(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{secrets: keys, now: time.Now}, nil
+}
+
+// Sign returns the header value for body, signed now.
+func (s *Signer) Sign(body []byte) string {
+	timestamp := s.now().Unix()
+
+	var header strings.Builder
+	header.WriteString(timestampKey + "=" + strconv.FormatInt(timestamp, 10))
+	for _, secret := range s.secrets {
+		header.WriteString("," + signatureKey + "=" + hex.EncodeToString(compute(secret, timestamp, body)))
+	}
+
+	return header.String()
+}
+
+// Verifier checks signature headers against the receiver's active secrets.
+type Verifier struct {
+	secrets   [][]byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewVerifier returns a Verifier accepting signatures made with any of the secrets at most
+// tolerance away from now. A tolerance of 0 uses DefaultTolerance.
+func NewVerifier(tolerance time.Duration, secrets ...string) (*Verifier, error) {
+	keys, err := toKeys(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	return &Verifier{secrets: keys, tolerance: tolerance, now: time.Now}, nil
+}
+
+// Verify returns nil when header holds a signature of body made with one of the secrets within
+// the tolerance.
+func (v *Verifier) Verify(header string, body []byte) error {
+	if header == "" {
+		return ErrMissingHeader
+	}
+
+	timestamp, signatures, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	age := v.now().Sub(time.Unix(timestamp, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return ErrTimestampExpired
+	}
+
+	for _, secret := range v.secrets {
+		expected := compute(secret, timestamp, body)
+		for _, signature := range signatures {
+			if hmac.Equal(expected, signature) {
+				return nil
+			}
+		}
+	}
+
+	return ErrSignatureMismatch
+}
+
+// VerifyRequest verifies an incoming request and returns its body, which it has to read for that.
+// The request body is replaced, so handlers can still read it.
+func (v *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("signature: failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, v.Verify(r.Header.Get(Header), body)
+}
+
+func compute(secret []byte, timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// parseHeader reads the timestamp and the v1 signatures, ignoring schemes it does not know so
+// senders can add new ones.
+func parseHeader(header string) (int64, [][]byte, error) {
+	var (
+		timestamp  int64
+		signatures [][]byte
+		seenTime   bool
+	)
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return 0, nil, ErrInvalidHeader
+		}
+
+		switch key {
+		case timestampKey:
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, ErrInvalidHeader
+			}
+			timestamp, seenTime = parsed, true
+		case signatureKey:
+			decoded, err := hex.DecodeString(value)
+			if err != nil {
+				return 0, nil, ErrInvalidHeader
+			}
+			signatures = append(signatures, decoded)
+		}
+	}
+
+	if !seenTime || len(signatures) == 0 {
+		return 0, nil, ErrInvalidHeader
+	}
+
+	return timestamp, signatures, nil
+}
+
+func toKeys(secrets []string) ([][]byte, error) {
+	keys := make([][]byte, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			keys = append(keys, []byte(secret))
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoSecrets
+	}
+
+	return keys, nil
+}
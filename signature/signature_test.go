@@ -0,0 +1,111 @@
+package signature
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var signedAt = time.Date(2025, 1, 2, 6, 0, 0, 0, time.UTC)
+
+func newTestSigner(t *testing.T, secrets ...string) *Signer {
+	signer, err := NewSigner(secrets...)
+	assert.NoError(t, err)
+	signer.now = func() time.Time { return signedAt }
+	return signer
+}
+
+func newTestVerifier(t *testing.T, now time.Time, secrets ...string) *Verifier {
+	verifier, err := NewVerifier(time.Minute, secrets...)
+	assert.NoError(t, err)
+	verifier.now = func() time.Time { return now }
+	return verifier
+}
+
+func TestSign_Format(t *testing.T) {
+	header := newTestSigner(t, "new", "old").Sign([]byte(`{"to":"+905551234567"}`))
+
+	parts := strings.Split(header, ",")
+	assert.Len(t, parts, 3)
+	assert.Equal(t, "t=1735797600", parts[0])
+	assert.True(t, strings.HasPrefix(parts[1], "v1="))
+	assert.True(t, strings.HasPrefix(parts[2], "v1="))
+	assert.NotEqual(t, parts[1], parts[2])
+}
+
+func TestVerify_RoundTrip(t *testing.T) {
+	body := []byte(`{"to":"+905551234567","content":"Hello"}`)
+	header := newTestSigner(t, "secret").Sign(body)
+
+	assert.NoError(t, newTestVerifier(t, signedAt, "secret").Verify(header, body))
+}
+
+func TestVerify_SecretRotation(t *testing.T) {
+	body := []byte(`{}`)
+	header := newTestSigner(t, "new", "old").Sign(body)
+
+	assert.NoError(t, newTestVerifier(t, signedAt, "old").Verify(header, body), "receiver not rotated yet")
+	assert.NoError(t, newTestVerifier(t, signedAt, "new").Verify(header, body), "receiver already rotated")
+	assert.ErrorIs(t, newTestVerifier(t, signedAt, "other").Verify(header, body), ErrSignatureMismatch)
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	body := []byte(`{"content":"Hello"}`)
+	header := newTestSigner(t, "secret").Sign(body)
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+		now    time.Time
+		want   error
+	}{
+		{name: "missing header", header: "", body: body, now: signedAt, want: ErrMissingHeader},
+		{name: "tampered body", header: header, body: []byte(`{"content":"Bye"}`), now: signedAt, want: ErrSignatureMismatch},
+		{name: "too old", header: header, body: body, now: signedAt.Add(2 * time.Minute), want: ErrTimestampExpired},
+		{name: "from the future", header: header, body: body, now: signedAt.Add(-2 * time.Minute), want: ErrTimestampExpired},
+		{name: "no timestamp", header: "v1=abcd", body: body, now: signedAt, want: ErrInvalidHeader},
+		{name: "no signature", header: "t=1735797600", body: body, now: signedAt, want: ErrInvalidHeader},
+		{name: "not hex", header: "t=1735797600,v1=xyz", body: body, now: signedAt, want: ErrInvalidHeader},
+		{name: "garbage", header: "sha256 abc", body: body, now: signedAt, want: ErrInvalidHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestVerifier(t, tt.now, "secret").Verify(tt.header, tt.body)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestVerify_IgnoresUnknownSchemes(t *testing.T) {
+	body := []byte(`{}`)
+	header := newTestSigner(t, "secret").Sign(body) + ",v0=legacy"
+
+	assert.NoError(t, newTestVerifier(t, signedAt, "secret").Verify(header, body))
+}
+
+func TestVerifyRequest_KeepsBody(t *testing.T) {
+	body := `{"content":"Hello"}`
+	request := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	request.Header.Set(Header, newTestSigner(t, "secret").Sign([]byte(body)))
+
+	read, err := newTestVerifier(t, signedAt, "secret").VerifyRequest(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(read))
+	remaining, _ := io.ReadAll(request.Body)
+	assert.Equal(t, body, string(remaining))
+}
+
+func TestNewSigner_RequiresSecret(t *testing.T) {
+	_, err := NewSigner("", "")
+	assert.ErrorIs(t, err, ErrNoSecrets)
+
+	_, err = NewVerifier(0)
+	assert.ErrorIs(t, err, ErrNoSecrets)
+}